@@ -4,21 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs"
 	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/text_to_speech"
 )
 
 func main() {
-	// Get API key from environment variable
-	apiKey := os.Getenv("ELEVENLABS_API_KEY")
-	if apiKey == "" {
-		log.Fatal("ELEVENLABS_API_KEY environment variable is required")
-	}
-
-	// Create client
-	client, err := elevenlabs.NewClient(apiKey)
+	// Create client from ELEVENLABS_* environment variables and the selected config profile
+	client, err := elevenlabs.NewClientFromEnv()
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -0,0 +1,9 @@
+package elevenlabs
+
+import "github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+
+// Cache stores generated audio for WithTTSCache. See the cache package for memory and disk caches.
+type Cache = core.Cache
+
+// CacheEntry is a cached response body with the headers it was returned with
+type CacheEntry = core.CacheEntry
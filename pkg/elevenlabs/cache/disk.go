@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// diskFileSuffix names the entry files of a DiskCache
+const diskFileSuffix = ".cache"
+
+// DiskCache keeps entries as files in a directory, so they survive restarts and can be shared by processes.
+// Each file holds a JSON header line followed by the audio.
+type DiskCache struct {
+	dir string
+}
+
+// diskHeader is the first line of an entry file
+type diskHeader struct {
+	Key     string      `json:"key"`
+	VoiceID string      `json:"voice_id"`
+	Header  http.Header `json:"header,omitempty"`
+	// Expires is the expiry time in Unix nanoseconds, or zero if the entry does not expire
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// expired reports whether the entry has expired
+func (h diskHeader) expired() bool {
+	return h.Expires != 0 && time.Now().UnixNano() > h.Expires
+}
+
+// NewDiskCache creates a cache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get implements core.Cache
+func (c *DiskCache) Get(ctx context.Context, key string) (*core.CacheEntry, bool, error) {
+	path := c.path(key)
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header, err := readDiskHeader(reader)
+	if err != nil {
+		return nil, false, err
+	}
+	// Distinct keys could share a file name only through a hash collision
+	if header.Key != key {
+		return nil, false, nil
+	}
+	if header.expired() {
+		os.Remove(path)
+		return nil, false, nil
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	return &core.CacheEntry{VoiceID: header.VoiceID, Data: data, Header: header.Header}, true, nil
+}
+
+// Set implements core.Cache. The entry is written to a temporary file and renamed into place,
+// so readers never see a partial entry.
+func (c *DiskCache) Set(ctx context.Context, key string, entry *core.CacheEntry, ttl time.Duration) error {
+	header := diskHeader{Key: key, VoiceID: entry.VoiceID, Header: entry.Header}
+	if ttl > 0 {
+		header.Expires = time.Now().Add(ttl).UnixNano()
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	writer.Write(line)
+	writer.WriteByte('\n')
+	writer.Write(entry.Data)
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// InvalidateVoice implements core.Cache
+func (c *DiskCache) InvalidateVoice(ctx context.Context, voiceID string) error {
+	return c.removeIf(ctx, func(header diskHeader) bool {
+		return header.VoiceID == voiceID
+	})
+}
+
+// Prune removes expired entries, which are otherwise only removed when they are read
+func (c *DiskCache) Prune(ctx context.Context) error {
+	return c.removeIf(ctx, diskHeader.expired)
+}
+
+// removeIf removes the entries whose header matches
+func (c *DiskCache) removeIf(ctx context.Context, match func(diskHeader) bool) error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if file.IsDir() || !strings.HasSuffix(file.Name(), diskFileSuffix) {
+			continue
+		}
+
+		path := filepath.Join(c.dir, file.Name())
+		header, err := readDiskHeaderFile(path)
+		if err != nil {
+			// Unreadable entries are left for Get to report
+			continue
+		}
+		if match(header) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// path returns the file of an entry, named by a hash so that any key is a valid file name
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+diskFileSuffix)
+}
+
+// readDiskHeaderFile reads the header of an entry file
+func readDiskHeaderFile(path string) (diskHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return diskHeader{}, err
+	}
+	defer file.Close()
+	return readDiskHeader(bufio.NewReader(file))
+}
+
+// readDiskHeader reads the header line of an entry
+func readDiskHeader(reader *bufio.Reader) (diskHeader, error) {
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return diskHeader{}, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	var header diskHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return diskHeader{}, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+	return header, nil
+}
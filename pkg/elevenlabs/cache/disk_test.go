@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &core.CacheEntry{VoiceID: "v", Data: []byte("audio\nwith newline"), Header: http.Header{"Content-Type": {"audio/mpeg"}}}
+	if err := c.Set(ctx, "key", want, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := c.Get(ctx, "key")
+	if err != nil || !ok {
+		t.Fatalf("expected the entry, got %v, %v", ok, err)
+	}
+	if got.VoiceID != "v" || string(got.Data) != string(want.Data) || got.Header.Get("Content-Type") != "audio/mpeg" {
+		t.Errorf("unexpected entry %+v", got)
+	}
+	if _, ok, _ := c.Get(ctx, "other"); ok {
+		t.Error("expected a miss for another key")
+	}
+}
+
+func TestDiskCacheExpiresAndInvalidates(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Set(ctx, "short", entry("v1", 1), 10*time.Millisecond)
+	c.Set(ctx, "v1", entry("v1", 1), 0)
+	c.Set(ctx, "v2", entry("v2", 1), 0)
+
+	time.Sleep(20 * time.Millisecond)
+	if err := c.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InvalidateVoice(ctx, "v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected only the entry of v2 to remain, got %d files", len(files))
+	}
+	if _, ok, _ := c.Get(ctx, "v2"); !ok {
+		t.Error("expected the entry of v2 to be kept")
+	}
+}
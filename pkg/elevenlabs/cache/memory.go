@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// MemoryCache keeps entries in memory, evicting the least recently used once their audio exceeds a size cap
+type MemoryCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+	voices   map[string]map[string]struct{}
+}
+
+// memoryEntry is an entry of a MemoryCache
+type memoryEntry struct {
+	key     string
+	entry   *core.CacheEntry
+	expires time.Time
+}
+
+// NewMemoryCache creates a cache holding at most maxBytes of audio; zero or less leaves it unbounded
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		voices:   make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements core.Cache
+func (c *MemoryCache) Get(ctx context.Context, key string) (*core.CacheEntry, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := elem.Value.(*memoryEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.remove(elem)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(elem)
+	// Callers get their own copy, so they cannot change the cached entry
+	return e.entry.Clone(), true, nil
+}
+
+// Set implements core.Cache. Entries larger than the size cap are not stored.
+func (c *MemoryCache) Set(ctx context.Context, key string, entry *core.CacheEntry, ttl time.Duration) error {
+	size := int64(len(entry.Data))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return nil
+	}
+
+	e := &memoryEntry{key: key, entry: entry}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(e)
+	c.size += size
+	if c.voices[entry.VoiceID] == nil {
+		c.voices[entry.VoiceID] = make(map[string]struct{})
+	}
+	c.voices[entry.VoiceID][key] = struct{}{}
+
+	// Evict the least recently used entries
+	for c.maxBytes > 0 && c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// InvalidateVoice implements core.Cache
+func (c *MemoryCache) InvalidateVoice(ctx context.Context, voiceID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.voices[voiceID] {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired entries not yet removed
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the total size of the cached audio in bytes
+func (c *MemoryCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// remove deletes an entry; the caller must hold the lock
+func (c *MemoryCache) remove(elem *list.Element) {
+	e := c.order.Remove(elem).(*memoryEntry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.entry.Data))
+
+	voiceID := e.entry.VoiceID
+	delete(c.voices[voiceID], e.key)
+	if len(c.voices[voiceID]) == 0 {
+		delete(c.voices, voiceID)
+	}
+}
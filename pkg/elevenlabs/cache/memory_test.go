@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// entry returns a cache entry of size bytes for the voice
+func entry(voiceID string, size int) *core.CacheEntry {
+	return &core.CacheEntry{VoiceID: voiceID, Data: make([]byte, size), Header: http.Header{"Content-Type": {"audio/mpeg"}}}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+	c.Set(ctx, "a", entry("v", 4), 0)
+	c.Set(ctx, "b", entry("v", 4), 0)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", entry("v", 4), 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Error("expected the recently read entry to be kept")
+	}
+	if c.Len() != 2 || c.Size() != 8 {
+		t.Errorf("expected 2 entries of 8 bytes, got %d of %d", c.Len(), c.Size())
+	}
+
+	c.Set(ctx, "big", entry("v", 11), 0)
+	if _, ok, _ := c.Get(ctx, "big"); ok {
+		t.Error("expected an entry larger than the cap not to be stored")
+	}
+}
+
+func TestMemoryCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+	c.Set(ctx, "a", entry("v", 1), 10*time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("expected the entry before it expires")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("expected the entry to expire")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected the expired entry to be removed, got %d entries", c.Len())
+	}
+}
+
+func TestMemoryCacheInvalidateVoice(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+	c.Set(ctx, "a", entry("v1", 1), 0)
+	c.Set(ctx, "b", entry("v1", 1), 0)
+	c.Set(ctx, "c", entry("v2", 1), 0)
+
+	c.InvalidateVoice(ctx, "v1")
+	if c.Len() != 1 {
+		t.Fatalf("expected only the entry of v2 to remain, got %d entries", c.Len())
+	}
+	if _, ok, _ := c.Get(ctx, "c"); !ok {
+		t.Error("expected the entry of v2 to be kept")
+	}
+}
+
+func TestMemoryCacheGetReturnsCopy(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+	c.Set(ctx, "a", &core.CacheEntry{VoiceID: "v", Data: []byte("audio"), Header: http.Header{"Content-Type": {"audio/mpeg"}}}, 0)
+
+	got, _, _ := c.Get(ctx, "a")
+	got.Data[0] = 'X'
+	got.Header.Set("Content-Type", "changed")
+
+	again, _, _ := c.Get(ctx, "a")
+	if string(again.Data) != "audio" || again.Header.Get("Content-Type") != "audio/mpeg" {
+		t.Errorf("expected the cached entry to be unchanged, got %q with %v", again.Data, again.Header)
+	}
+}
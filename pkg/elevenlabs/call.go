@@ -0,0 +1,76 @@
+package elevenlabs
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// FileUpload is a file sent in a multipart form by DoMultipart
+type FileUpload = core.FileUpload
+
+// Do calls any API endpoint, such as one not yet wrapped by a service client, with the client's
+// authentication, retries, middleware and logging, and decodes the response into out.
+//
+// reqBody is sent as-is if it is an io.Reader or []byte and encoded as JSON otherwise; nil sends no body.
+// out may be nil to discard the response, a *[]byte or io.Writer to receive the raw body, or a pointer
+// to decode the JSON response into. Error responses are returned as *APIError.
+func (c *Client) Do(ctx context.Context, method, path string, reqBody, out interface{}, opts ...RequestOption) (*ResponseMetadata, error) {
+	return c.httpClient.Do(ctx, method, path, reqBody, out, opts...)
+}
+
+// DoStream calls any API endpoint like Do but returns the response with its body unread, for endpoints
+// that stream audio or events. The caller must close the response body.
+func (c *Client) DoStream(ctx context.Context, method, path string, reqBody interface{}, opts ...RequestOption) (*http.Response, error) {
+	return c.httpClient.DoStream(ctx, method, path, reqBody, opts...)
+}
+
+// DoMultipart calls any API endpoint with a multipart form of files and fields and decodes the response
+// into out like Do
+func (c *Client) DoMultipart(ctx context.Context, method, path string, files []FileUpload, fields map[string]string, out interface{}, opts ...RequestOption) (*ResponseMetadata, error) {
+	return c.httpClient.DoMultipart(ctx, method, path, files, fields, out, opts...)
+}
+
+// Call calls any API endpoint like Client.Do and returns the JSON response decoded as T:
+//
+//	models, err := elevenlabs.Call[[]Model](ctx, client, http.MethodGet, "v1/models", nil)
+func Call[T any](ctx context.Context, c *Client, method, path string, reqBody interface{}, opts ...RequestOption) (T, error) {
+	var out T
+	_, err := c.Do(ctx, method, path, reqBody, &out, opts...)
+	return out, err
+}
+
+// CallWithMetadata calls any API endpoint like Call and also returns the response metadata
+func CallWithMetadata[T any](ctx context.Context, c *Client, method, path string, reqBody interface{}, opts ...RequestOption) (T, *ResponseMetadata, error) {
+	var out T
+	metadata, err := c.Do(ctx, method, path, reqBody, &out, opts...)
+	return out, metadata, err
+}
+
+// CallMultipart calls any API endpoint with a multipart form like Client.DoMultipart and returns the JSON
+// response decoded as T
+func CallMultipart[T any](ctx context.Context, c *Client, method, path string, files []FileUpload, fields map[string]string, opts ...RequestOption) (T, error) {
+	var out T
+	_, err := c.DoMultipart(ctx, method, path, files, fields, &out, opts...)
+	return out, err
+}
+
+// ListEndpoint describes the parameters and fields of a cursor-paginated endpoint called with List
+type ListEndpoint = core.ListEndpoint
+
+// ErrNoMorePages is returned by a pager after the last page
+var ErrNoMorePages = core.ErrNoMorePages
+
+// List returns a pager over any cursor-paginated endpoint, decoding the items of each page as T:
+//
+//	pager := elevenlabs.List[HistoryItem](client, elevenlabs.ListEndpoint{
+//		Path:        "v1/history",
+//		ItemsField:  "history",
+//		CursorParam: "start_after_history_item_id",
+//		CursorField: "last_history_item_id",
+//	}, 100)
+//	items, err := pager.All(ctx)
+func List[T any](c *Client, endpoint ListEndpoint, pageSize int, opts ...RequestOption) *core.Pager[T] {
+	return core.NewListPager[T](c.httpClient, endpoint, pageSize, opts...)
+}
@@ -0,0 +1,213 @@
+// Package cassette records interactions with the ElevenLabs API to a file and replays them offline, so
+// integration tests captured once with a real API key can run in CI without one.
+//
+//	rec, err := cassette.New("testdata/narrate.json", cassette.ModeOnce)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer rec.Save()
+//
+//	client, err := elevenlabs.NewClient(os.Getenv("ELEVENLABS_API_KEY"), rec.Options()...)
+//
+// API keys are never written to the cassette. Requests are matched by method, path, query and normalized JSON
+// body, so the order of keys and whitespace do not matter.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []*Interaction      `json:"interactions"`
+	WebSockets   []*WebSocketSession `json:"websockets,omitempty"`
+}
+
+// Interaction is a recorded HTTP request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   *Body       `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       *Body       `json:"body,omitempty"`
+}
+
+// Body is a recorded message body. JSON bodies are kept as JSON and other bodies as base64 data, unless only
+// their hash was recorded.
+type Body struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+	Data []byte          `json:"data,omitempty"`
+	// SHA256 and Size describe a body whose content was not recorded
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int    `json:"size,omitempty"`
+}
+
+// WebSocketSession is a recorded WebSocket session
+type WebSocketSession struct {
+	Path   string   `json:"path"`
+	Query  string   `json:"query,omitempty"`
+	Frames []*Frame `json:"frames"`
+}
+
+// Directions of WebSocket frames
+const (
+	FrameSent     = "sent"
+	FrameReceived = "received"
+)
+
+// Frame is a recorded WebSocket message
+type Frame struct {
+	Direction string `json:"direction"`
+	// Type is the WebSocket message type, such as websocket.TextMessage
+	Type int   `json:"type"`
+	Body *Body `json:"body"`
+	// AudioSHA256 and AudioSize describe the base64 audio field of a JSON frame that was not recorded
+	AudioSHA256 string `json:"audio_sha256,omitempty"`
+	AudioSize   int    `json:"audio_size,omitempty"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a file, creating its directory if needed
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// newBody records data, keeping only its hash if hash is set
+func newBody(data []byte, hash bool) *Body {
+	if len(data) == 0 {
+		return nil
+	}
+	if hash {
+		sum := sha256.Sum256(data)
+		return &Body{SHA256: hex.EncodeToString(sum[:]), Size: len(data)}
+	}
+	if json.Valid(data) {
+		return &Body{JSON: bytes.Clone(data)}
+	}
+	return &Body{Data: bytes.Clone(data)}
+}
+
+// Bytes returns the recorded body. A body recorded as a hash is replaced by deterministic placeholder bytes of
+// the recorded size.
+func (b *Body) Bytes() []byte {
+	switch {
+	case b == nil:
+		return nil
+	case b.JSON != nil:
+		var compact bytes.Buffer
+		if json.Compact(&compact, b.JSON) != nil {
+			return bytes.Clone(b.JSON)
+		}
+		return compact.Bytes()
+	case b.SHA256 != "":
+		return placeholder(b.SHA256, b.Size)
+	default:
+		return bytes.Clone(b.Data)
+	}
+}
+
+// placeholder returns size bytes derived from a hash
+func placeholder(hash string, size int) []byte {
+	seed := sha256.Sum256([]byte(hash))
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = seed[i%len(seed)]
+	}
+	return data
+}
+
+// normalizeBody returns the form of a request body that requests are matched on: JSON with sorted keys and no
+// whitespace, or "" for other bodies, such as multipart uploads with random boundaries
+func normalizeBody(data []byte) string {
+	var value interface{}
+	if len(data) == 0 || json.Unmarshal(data, &value) != nil {
+		return ""
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(normalized)
+}
+
+// scrubbedQuery lists the query parameters that can carry credentials
+var scrubbedQuery = []string{"xi_api_key", "authorization", "single_use_token"}
+
+// normalizeQuery returns a query string with sorted parameters and without credentials
+func normalizeQuery(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	for _, key := range scrubbedQuery {
+		query.Del(key)
+	}
+	return query.Encode()
+}
+
+// scrubHeader returns a copy of header without the given headers
+func scrubHeader(header http.Header, names []string) http.Header {
+	header = header.Clone()
+	for _, name := range names {
+		header.Del(name)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
+
+// defaultScrubbedHeaders are the headers never written to a cassette
+var defaultScrubbedHeaders = []string{core.APIKeyHeader, "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// isAudio reports whether a content type is audio
+func isAudio(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "audio/")
+}
@@ -0,0 +1,276 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs"
+)
+
+// ErrNoInteraction is returned in replay mode for requests and WebSocket sessions the cassette does not hold
+var ErrNoInteraction = errors.New("cassette: no recorded interaction")
+
+// Mode selects whether a Recorder talks to the API or replays a cassette
+type Mode int
+
+const (
+	// ModeReplay serves every request from the cassette and fails those it does not hold
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to the API and records it, replacing the cassette on Save
+	ModeRecord
+	// ModeOnce records if the cassette file does not exist and replays it otherwise
+	ModeOnce
+)
+
+// Recorder is an http.RoundTripper and WebSocket middleware that records interactions with the API to a
+// cassette or replays them from it. Each recorded interaction is replayed once, in the order it was recorded
+// among those matching a request.
+type Recorder struct {
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	hashAudio    bool
+	scrubHeaders []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[*Interaction]bool
+	usedWS   map[*WebSocketSession]bool
+}
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithTransport sets the transport that records requests; the default is http.DefaultTransport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithAudioHashes records audio bodies and the audio of WebSocket frames as hashes, keeping cassettes small.
+// Replayed audio is placeholder data of the recorded size.
+func WithAudioHashes() Option {
+	return func(r *Recorder) {
+		r.hashAudio = true
+	}
+}
+
+// WithScrubbedHeaders leaves more headers out of the cassette, in addition to credentials and cookies
+func WithScrubbedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.scrubHeaders = append(r.scrubHeaders, names...)
+	}
+}
+
+// New creates a recorder of the cassette at path. In replay mode the cassette is loaded now.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:         path,
+		mode:         mode,
+		transport:    http.DefaultTransport,
+		scrubHeaders: append([]string(nil), defaultScrubbedHeaders...),
+		cassette:     &Cassette{},
+		used:         make(map[*Interaction]bool),
+		usedWS:       make(map[*WebSocketSession]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeOnce {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+	}
+	return r, nil
+}
+
+// Mode returns ModeRecord or ModeReplay
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Recording reports whether the recorder sends requests to the API
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// HTTPClient returns an HTTP client that sends its requests through the recorder
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Options returns the client options that send requests and WebSocket sessions through the recorder. Requests
+// missing from the cassette fail without retries, so they must follow any retry configuration.
+func (r *Recorder) Options() []elevenlabs.Option {
+	return []elevenlabs.Option{
+		elevenlabs.WithHTTPClient(r.HTTPClient()),
+		elevenlabs.WithWebSocketMiddleware(r.WebSocketMiddleware()),
+		withoutReplayRetries,
+	}
+}
+
+// withoutReplayRetries stops the client from retrying requests missing from the cassette
+func withoutReplayRetries(c *elevenlabs.Config) {
+	retryable := c.RetryConfig.RetryableError
+	c.RetryConfig.RetryableError = func(err error) bool {
+		if errors.Is(err, ErrNoInteraction) {
+			return false
+		}
+		return retryable == nil || retryable(err)
+	}
+}
+
+// Save writes the recorded cassette. It does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Read the request body, leaving the request of the caller untouched
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// record sends a request to the API and records it with its response
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Streams are recorded once they have been received in full
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  normalizeQuery(req.URL.RawQuery),
+			Header: scrubHeader(req.Header, r.scrubHeaders),
+			// Uploads other than JSON are kept as hashes, since they are not matched on
+			Body: newBody(body, !json.Valid(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header, r.scrubHeaders),
+			Body:       newBody(respBody, r.hashAudio && isAudio(resp.Header.Get("Content-Type"))),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	return resp, nil
+}
+
+// replay returns the recorded response to the first unused interaction matching a request
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	query := normalizeQuery(req.URL.RawQuery)
+	normalized := normalizeBody(body)
+
+	r.mu.Lock()
+	var match *Interaction
+	for _, interaction := range r.cassette.Interactions {
+		recorded := interaction.Request
+		if r.used[interaction] || recorded.Method != req.Method || recorded.Path != req.URL.Path || recorded.Query != query {
+			continue
+		}
+		if normalizeBody(recorded.Body.Bytes()) != normalized {
+			continue
+		}
+		match = interaction
+		r.used[interaction] = true
+		break
+	}
+	r.mu.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+	}
+
+	respBody := match.Response.Body.Bytes()
+	header := match.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the recorded interactions and WebSocket sessions that have not been replayed, for example
+// to check that a test made every request it was recorded with
+func (r *Recorder) Unused() ([]*Interaction, []*WebSocketSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var interactions []*Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if !r.used[interaction] {
+			interactions = append(interactions, interaction)
+		}
+	}
+	var sessions []*WebSocketSession
+	for _, session := range r.cassette.WebSockets {
+		if !r.usedWS[session] {
+			sessions = append(sessions, session)
+		}
+	}
+	return interactions, sessions
+}
@@ -0,0 +1,305 @@
+package cassette
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// scrubbedFields lists the fields of WebSocket messages that can carry credentials
+var scrubbedFields = []string{"xi_api_key", "authorization"}
+
+// WebSocketMiddleware returns middleware that records the messages of WebSocket sessions or replays them.
+// A replayed session delivers each received message once the client has sent the messages that preceded it
+// in the recording, and closes normally after the last one.
+func (r *Recorder) WebSocketMiddleware() core.WebSocketMiddleware {
+	return func(next core.WebSocketDialer) core.WebSocketDialer {
+		return core.WebSocketDialerFunc(func(ctx context.Context, rawURL string, header http.Header) (core.WebSocketConn, *http.Response, error) {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				return nil, nil, err
+			}
+			if r.mode == ModeReplay {
+				return r.replayWebSocket(u)
+			}
+
+			conn, resp, err := next.DialWebSocket(ctx, rawURL, header)
+			if err != nil || conn == nil {
+				return conn, resp, err
+			}
+
+			session := &WebSocketSession{Path: u.Path, Query: normalizeQuery(u.RawQuery), Frames: []*Frame{}}
+			r.mu.Lock()
+			r.cassette.WebSockets = append(r.cassette.WebSockets, session)
+			r.mu.Unlock()
+			return &recordingConn{WebSocketConn: conn, recorder: r, session: session}, resp, nil
+		})
+	}
+}
+
+// recordFrame appends a message to a recorded session
+func (r *Recorder) recordFrame(session *WebSocketSession, direction string, messageType int, data []byte) {
+	frame := &Frame{Direction: direction, Type: messageType}
+
+	switch {
+	case direction == FrameSent:
+		frame.Body = newBody(scrubMessage(data), false)
+	case r.hashAudio && messageType == websocket.BinaryMessage:
+		frame.Body = newBody(data, true)
+	case r.hashAudio:
+		data, frame.AudioSHA256, frame.AudioSize = hashAudioField(data)
+		frame.Body = newBody(data, false)
+	default:
+		frame.Body = newBody(data, false)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.Frames = append(session.Frames, frame)
+}
+
+// replayWebSocket opens a replayed connection for the first unused session recorded with the URL
+func (r *Recorder) replayWebSocket(u *url.URL) (core.WebSocketConn, *http.Response, error) {
+	query := normalizeQuery(u.RawQuery)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.cassette.WebSockets {
+		if r.usedWS[session] || session.Path != u.Path || session.Query != query {
+			continue
+		}
+		r.usedWS[session] = true
+
+		resp := &http.Response{
+			Status:     "101 Switching Protocols",
+			StatusCode: http.StatusSwitchingProtocols,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}
+		return newReplayConn(session), resp, nil
+	}
+	return nil, nil, fmt.Errorf("%w for WebSocket %s", ErrNoInteraction, u.RequestURI())
+}
+
+// recordingConn records the messages of a connection to the API
+type recordingConn struct {
+	core.WebSocketConn
+	recorder *Recorder
+	session  *WebSocketSession
+}
+
+// WriteMessage implements core.WebSocketConn
+func (c *recordingConn) WriteMessage(messageType int, data []byte) error {
+	if err := c.WebSocketConn.WriteMessage(messageType, data); err != nil {
+		return err
+	}
+	c.recorder.recordFrame(c.session, FrameSent, messageType, data)
+	return nil
+}
+
+// ReadMessage implements core.WebSocketConn
+func (c *recordingConn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.WebSocketConn.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+	c.recorder.recordFrame(c.session, FrameReceived, messageType, data)
+	return messageType, data, nil
+}
+
+// replayedFrame is a received message of a replayed session with the number of messages sent before it
+type replayedFrame struct {
+	frame *Frame
+	after int
+}
+
+// replayConn plays back the received messages of a recorded session
+type replayConn struct {
+	frames []replayedFrame
+
+	mu       sync.Mutex
+	changed  chan struct{}
+	sent     int
+	next     int
+	closed   bool
+	deadline time.Time
+}
+
+// newReplayConn creates a connection replaying a session
+func newReplayConn(session *WebSocketSession) *replayConn {
+	c := &replayConn{changed: make(chan struct{})}
+	sent := 0
+	for _, frame := range session.Frames {
+		if frame.Direction == FrameSent {
+			sent++
+			continue
+		}
+		c.frames = append(c.frames, replayedFrame{frame: frame, after: sent})
+	}
+	return c
+}
+
+// notify wakes a waiting ReadMessage; the caller must hold the lock
+func (c *replayConn) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// WriteMessage implements core.WebSocketConn. Messages are counted but not compared with the recording.
+func (c *replayConn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+	if messageType == websocket.TextMessage || messageType == websocket.BinaryMessage {
+		c.sent++
+		c.notify()
+	}
+	return nil
+}
+
+// ReadMessage implements core.WebSocketConn
+func (c *replayConn) ReadMessage() (int, []byte, error) {
+	for {
+		c.mu.Lock()
+		switch {
+		case c.closed:
+			c.mu.Unlock()
+			return 0, nil, net.ErrClosed
+		case c.next >= len(c.frames):
+			c.mu.Unlock()
+			return 0, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+		case c.sent >= c.frames[c.next].after:
+			frame := c.frames[c.next].frame
+			c.next++
+			c.mu.Unlock()
+			return frame.Type, replayFrameData(frame), nil
+		}
+		changed, deadline := c.changed, c.deadline
+		c.mu.Unlock()
+
+		// Wait for the client to send the messages the next one answers
+		if deadline.IsZero() {
+			<-changed
+			continue
+		}
+		timer := time.NewTimer(time.Until(deadline))
+		select {
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+			return 0, nil, os.ErrDeadlineExceeded
+		}
+	}
+}
+
+// SetReadDeadline implements core.WebSocketConn
+func (c *replayConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	c.notify()
+	return nil
+}
+
+// SetCloseHandler implements core.WebSocketConn; a replayed session sends no control messages
+func (c *replayConn) SetCloseHandler(handler func(code int, text string) error) {}
+
+// SetPingHandler implements core.WebSocketConn
+func (c *replayConn) SetPingHandler(handler func(appData string) error) {}
+
+// SetPongHandler implements core.WebSocketConn
+func (c *replayConn) SetPongHandler(handler func(appData string) error) {}
+
+// Close implements core.WebSocketConn
+func (c *replayConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		c.notify()
+	}
+	return nil
+}
+
+// scrubMessage removes credentials from a JSON message
+func scrubMessage(data []byte) []byte {
+	var message map[string]json.RawMessage
+	if json.Unmarshal(data, &message) != nil {
+		return data
+	}
+
+	scrubbed := false
+	for _, field := range scrubbedFields {
+		if _, ok := message[field]; ok {
+			delete(message, field)
+			scrubbed = true
+		}
+	}
+	if !scrubbed {
+		return data
+	}
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return data
+	}
+	return encoded
+}
+
+// hashAudioField replaces the base64 audio field of a JSON message by its hash and size
+func hashAudioField(data []byte) ([]byte, string, int) {
+	var message map[string]json.RawMessage
+	var encoded string
+	if json.Unmarshal(data, &message) != nil || json.Unmarshal(message["audio"], &encoded) != nil || encoded == "" {
+		return data, "", 0
+	}
+	audio, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return data, "", 0
+	}
+
+	delete(message, "audio")
+	stripped, err := json.Marshal(message)
+	if err != nil {
+		return data, "", 0
+	}
+	body := newBody(audio, true)
+	return stripped, body.SHA256, body.Size
+}
+
+// replayFrameData returns the recorded message, restoring placeholder audio if only its hash was recorded
+func replayFrameData(frame *Frame) []byte {
+	data := frame.Body.Bytes()
+	if frame.AudioSHA256 == "" {
+		return data
+	}
+
+	var message map[string]json.RawMessage
+	if json.Unmarshal(data, &message) != nil {
+		return data
+	}
+	audio, _ := json.Marshal(base64.StdEncoding.EncodeToString(placeholder(frame.AudioSHA256, frame.AudioSize)))
+	message["audio"] = audio
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return data
+	}
+	return encoded
+}
@@ -30,11 +30,8 @@ func NewClient(apiKey string, opts ...Option) (*Client, error) {
 
 // NewClientWithConfig creates a new ElevenLabs client with the specified configuration
 func NewClientWithConfig(config Config) (*Client, error) {
-	if config.APIKey == "" {
-		return nil, &APIError{
-			statusCode: 0,
-			message:    "API key is required",
-		}
+	if config.APIKey == "" && config.Credentials == nil {
+		return nil, core.NewAPIError(0, nil, "API key is required")
 	}
 
 	// Create core HTTP client config
@@ -45,6 +42,26 @@ func NewClientWithConfig(config Config) (*Client, error) {
 		HTTPClient:  config.HTTPClient,
 		UserAgent:   config.UserAgent,
 		RetryConfig: config.RetryConfig,
+		RetryPolicy: config.RetryPolicy,
+		Middleware:  config.Middleware,
+		Logging:     config.Logging,
+		Metrics:     config.Metrics,
+		Tracer:      config.Tracer,
+
+		ConcurrencyLimiter:   config.ConcurrencyLimiter,
+		CircuitBreaker:       config.CircuitBreaker,
+		Credentials:          config.Credentials,
+		FailoverEnvironments: config.FailoverEnvironments,
+		StrictResidency:      config.StrictResidency,
+		Timeouts:             config.Timeouts,
+		Transport:            config.Transport,
+		DefaultVoiceID:       config.DefaultVoiceID,
+		DefaultModelID:       config.DefaultModelID,
+		TTSHedge:             config.TTSHedge,
+		TTSCache:             config.TTSCache,
+		TTSCacheTTL:          config.TTSCacheTTL,
+		Catalogs:             config.Catalogs,
+		WebSocketMiddleware:  config.WebSocketMiddleware,
 	}
 
 	httpClient := core.NewHTTPClient(coreConfig)
@@ -58,5 +75,10 @@ func NewClientWithConfig(config Config) (*Client, error) {
 	client.TextToSpeech = text_to_speech.NewClient(httpClient)
 	client.Voices = voices.NewClient(httpClient)
 
+	// Key pools load the quota of their keys through the client
+	if pool, ok := config.Credentials.(*KeyPool); ok {
+		pool.SetQuotaFetcher(client.fetchQuota(pool))
+	}
+
 	return client, nil
 }
@@ -1,7 +1,11 @@
 package elevenlabs
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
@@ -12,14 +16,17 @@ var (
 	ProductionEnv = core.Environment{
 		BaseURL:      "https://api.elevenlabs.io",
 		WebSocketURL: "wss://api.elevenlabs.io",
+		Region:       core.RegionGlobal,
 	}
 	ProductionUSEnv = core.Environment{
 		BaseURL:      "https://api.us.elevenlabs.io",
-		WebSocketURL: "wss://api.elevenlabs.io",
+		WebSocketURL: "wss://api.us.elevenlabs.io",
+		Region:       core.RegionUS,
 	}
 	ProductionEUEnv = core.Environment{
 		BaseURL:      "https://api.eu.residency.elevenlabs.io",
-		WebSocketURL: "wss://api.elevenlabs.io",
+		WebSocketURL: "wss://api.eu.residency.elevenlabs.io",
+		Region:       core.RegionEU,
 	}
 )
 
@@ -31,6 +38,38 @@ type Config struct {
 	HTTPClient  *http.Client
 	UserAgent   string
 	RetryConfig core.RetryConfig
+	RetryPolicy core.RetryPolicy
+	Middleware  []core.Middleware
+	Logging     core.LogConfig
+	Metrics     MetricsCollector
+	Tracer      core.Tracer
+	// ConcurrencyLimiter, if set, bounds in-flight requests and WebSocket sessions
+	ConcurrencyLimiter *core.ConcurrencyLimiter
+	// CircuitBreaker, if set, fails requests fast after sustained API failures
+	CircuitBreaker *core.CircuitBreaker
+	// Credentials, if set, supplies the API key for every request and WebSocket session instead of APIKey
+	Credentials core.CredentialProvider
+	// FailoverEnvironments are tried in order when an idempotent request fails in Environment
+	FailoverEnvironments []core.Environment
+	// StrictResidency refuses every request and WebSocket dial outside the region of Environment
+	StrictResidency bool
+	// Timeouts bounds the connection phases separately; streams are bounded by FirstByte and ReadIdle instead of Timeout
+	Timeouts core.Timeouts
+	// Transport configures proxies, TLS and connection pooling for HTTP requests and WebSocket sessions.
+	// It is ignored for HTTP requests when HTTPClient is set.
+	Transport core.TransportConfig
+	// DefaultVoiceID and DefaultModelID are used by text-to-speech requests that leave them empty
+	DefaultVoiceID string
+	DefaultModelID string
+	// TTSHedge, if set, hedges text-to-speech Convert and Stream requests
+	TTSHedge *core.HedgePolicy
+	// TTSCache, if set, serves repeated text-to-speech requests, keeping entries for TTSCacheTTL
+	TTSCache    core.Cache
+	TTSCacheTTL time.Duration
+	// Catalogs, if set, caches the list of voices for lookups by ID and name
+	Catalogs *core.CatalogConfig
+	// WebSocketMiddleware wraps the dialer of WebSocket sessions, for example to record or replay them
+	WebSocketMiddleware []core.WebSocketMiddleware
 }
 
 // DefaultConfig returns a default configuration
@@ -40,19 +79,55 @@ func DefaultConfig() Config {
 		Timeout:     240 * time.Second,
 		UserAgent:   "elevenlabs-golang/v1.0.0",
 		RetryConfig: core.DefaultRetryConfig(),
+		Timeouts:    core.DefaultTimeouts(),
 	}
 }
 
 // Option represents a configuration option
 type Option func(*Config)
 
-// WithTimeout sets the request timeout
+// WithTimeout sets the timeout of each non-streaming request attempt, including reading the response
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Config) {
 		c.Timeout = timeout
 	}
 }
 
+// WithTimeouts replaces the dial, TLS handshake, first-byte and read-idle timeouts
+func WithTimeouts(timeouts core.Timeouts) Option {
+	return func(c *Config) {
+		c.Timeouts = timeouts
+	}
+}
+
+// WithDialTimeout bounds opening a connection
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeouts.Dial = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout bounds the TLS handshake
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeouts.TLSHandshake = timeout
+	}
+}
+
+// WithFirstByteTimeout bounds the wait for the response headers of a stream or the WebSocket handshake
+func WithFirstByteTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeouts.FirstByte = timeout
+	}
+}
+
+// WithReadIdleTimeout bounds the wait for the next bytes of a stream or the next WebSocket message
+func WithReadIdleTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeouts.ReadIdle = timeout
+	}
+}
+
 // WithEnvironment sets the API environment
 func WithEnvironment(env core.Environment) Option {
 	return func(c *Config) {
@@ -80,3 +155,193 @@ func WithRetryConfig(retryConfig core.RetryConfig) Option {
 		c.RetryConfig = retryConfig
 	}
 }
+
+// WithRetryPolicy sets a custom retry policy, replacing the retry configuration
+func WithRetryPolicy(retryPolicy core.RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = retryPolicy
+	}
+}
+
+// WithMiddleware appends middleware that wraps every outgoing request, including WebSocket handshakes
+func WithMiddleware(middleware ...core.Middleware) Option {
+	return func(c *Config) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// WithWebSocketMiddleware appends middleware that wraps the dialer of every WebSocket session, so it can observe,
+// record or replace the messages of the session
+func WithWebSocketMiddleware(middleware ...core.WebSocketMiddleware) Option {
+	return func(c *Config) {
+		c.WebSocketMiddleware = append(c.WebSocketMiddleware, middleware...)
+	}
+}
+
+// WithLogger enables structured logging of requests and WebSocket sessions.
+// API keys and request text are redacted unless configured otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logging.Logger = logger
+	}
+}
+
+// WithLogVerbosity sets how much detail is logged for each request
+func WithLogVerbosity(verbosity core.LogVerbosity) Option {
+	return func(c *Config) {
+		c.Logging.Verbosity = verbosity
+	}
+}
+
+// WithMetrics sets the collector that receives one event per API call and WebSocket session
+func WithMetrics(collector MetricsCollector) Option {
+	return func(c *Config) {
+		c.Metrics = collector
+	}
+}
+
+// WithTracer sets the tracer that records spans around API calls, response streams and WebSocket sessions
+func WithTracer(tracer core.Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithConcurrencyLimit limits the number of concurrent requests and WebSocket sessions.
+// Use Client.SyncConcurrencyLimit to size the limit from the subscription tier.
+func WithConcurrencyLimit(limit int) Option {
+	return func(c *Config) {
+		c.ConcurrencyLimiter = core.NewConcurrencyLimiter(limit)
+	}
+}
+
+// WithConcurrencyLimiter sets a concurrency limiter, which may be shared with other clients using the same subscription
+func WithConcurrencyLimiter(limiter *core.ConcurrencyLimiter) Option {
+	return func(c *Config) {
+		c.ConcurrencyLimiter = limiter
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker that fails requests fast with ErrCircuitOpen after sustained
+// API failures, tracking text-to-speech, streaming and voices endpoints separately
+func WithCircuitBreaker(config core.CircuitBreakerConfig) Option {
+	return func(c *Config) {
+		c.CircuitBreaker = core.NewCircuitBreaker(config)
+	}
+}
+
+// WithCredentialProvider sets the provider asked for the API key before every request and WebSocket dial,
+// for example to rotate keys from a file or spread requests over several keys
+func WithCredentialProvider(provider core.CredentialProvider) Option {
+	return func(c *Config) {
+		c.Credentials = provider
+	}
+}
+
+// WithFailoverEnvironments sets environments that idempotent requests fail over to, in order, when the primary
+// environment returns 5xx responses, cannot be reached or has an open circuit. Other requests can opt in with
+// core.WithIdempotent.
+func WithFailoverEnvironments(envs ...core.Environment) Option {
+	return func(c *Config) {
+		c.FailoverEnvironments = append(c.FailoverEnvironments, envs...)
+	}
+}
+
+// WithStrictDataResidency refuses every request and WebSocket dial outside the region of the configured
+// environment with a *core.ResidencyError, including redirects and failover to other regions
+func WithStrictDataResidency() Option {
+	return func(c *Config) {
+		c.StrictResidency = true
+	}
+}
+
+// WithProxy routes HTTP requests and WebSocket sessions through an HTTP or HTTPS proxy.
+// Credentials can be given as user info in the URL.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Config) {
+		c.Transport.Proxy = proxyURL
+	}
+}
+
+// WithTransportConfig replaces the proxy, TLS and connection pool settings
+func WithTransportConfig(transport core.TransportConfig) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithRootCAs verifies servers and HTTPS proxies against the given certificate pool instead of the system pool
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Config) {
+		c.Transport.RootCAs = pool
+	}
+}
+
+// WithClientCertificate presents a client certificate to servers that request one (mutual TLS)
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(c *Config) {
+		c.Transport.Certificates = append(c.Transport.Certificates, cert)
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept open per host
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(c *Config) {
+		c.Transport.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept open
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Transport.IdleConnTimeout = timeout
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 for HTTP requests; it is enabled by default
+func WithHTTP2(enabled bool) Option {
+	return func(c *Config) {
+		c.Transport.DisableHTTP2 = !enabled
+	}
+}
+
+// WithDefaultVoice sets the voice used by text-to-speech requests without a voice ID
+func WithDefaultVoice(voiceID string) Option {
+	return func(c *Config) {
+		c.DefaultVoiceID = voiceID
+	}
+}
+
+// WithDefaultModel sets the model used by text-to-speech requests without a model ID
+func WithDefaultModel(modelID string) Option {
+	return func(c *Config) {
+		c.DefaultModelID = modelID
+	}
+}
+
+// WithTTSHedging hedges text-to-speech Convert and Stream requests: when no response has started within
+// policy.Delay, a duplicate is sent, for example to another region or with another key, and the first
+// response wins. Duplicates are billed, so this suits short interactive utterances.
+func WithTTSHedging(policy core.HedgePolicy) Option {
+	return func(c *Config) {
+		c.TTSHedge = &policy
+	}
+}
+
+// WithTTSCache serves repeated text-to-speech Convert, ConvertWithTimestamps and Stream requests from cache,
+// keeping entries for ttl (zero keeps them until evicted). Concurrent identical requests share one API call.
+// See the cache package for memory and disk caches.
+func WithTTSCache(cache core.Cache, ttl time.Duration) Option {
+	return func(c *Config) {
+		c.TTSCache = cache
+		c.TTSCacheTTL = ttl
+	}
+}
+
+// WithCatalogs caches the list of voices in client.Voices.Catalog for lookups by ID and name. The list is refreshed
+// in the background once it is older than ttl (zero keeps it until invalidated), and served stale if that fails.
+func WithCatalogs(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.Catalogs = &core.CatalogConfig{TTL: ttl}
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// bodyStats summarizes how a response body was consumed
+type bodyStats struct {
+	TimeToFirstByte time.Duration
+	Duration        time.Duration
+	Bytes           int64
+	Err             error
+}
+
+// bodyHooks are callbacks run while a response body is consumed
+type bodyHooks struct {
+	// onFirstByte runs when the first body byte is read
+	onFirstByte []func()
+	// onDone runs once, when the body is fully read, fails or is closed
+	onDone []func(bodyStats)
+}
+
+// trackedBody wraps a response body to measure reads and run hooks as it is consumed.
+// The request context is released on Close, or canceled when a read waits longer than readIdle.
+type trackedBody struct {
+	body     io.ReadCloser
+	start    time.Time
+	cancel   context.CancelFunc
+	readIdle time.Duration
+	hooks    bodyHooks
+
+	// idleTimer is created once and only reset and stopped afterwards; it is nil when reads are unbounded
+	idleTimer *time.Timer
+
+	mu      sync.Mutex
+	stats   bodyStats
+	done    bool
+	reading bool
+	idle    bool
+}
+
+// newTrackedBody wraps body; start is the time the request was issued and readIdle, if positive,
+// bounds each read
+func newTrackedBody(body io.ReadCloser, start time.Time, cancel context.CancelFunc, readIdle time.Duration, hooks bodyHooks) *trackedBody {
+	b := &trackedBody{
+		body:     body,
+		start:    start,
+		cancel:   cancel,
+		readIdle: readIdle,
+		hooks:    hooks,
+	}
+	if readIdle > 0 && cancel != nil {
+		b.idleTimer = time.AfterFunc(readIdle, b.expire)
+		b.idleTimer.Stop()
+	}
+	return b
+}
+
+// expire aborts the request if a read is still waiting when the idle timer fires
+func (b *trackedBody) expire() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.reading {
+		b.idle = true
+		b.cancel()
+	}
+}
+
+// Read reads from the underlying body, recording the first byte and total size. A read that waits longer
+// than readIdle aborts the request and fails with a *TimeoutError.
+func (b *trackedBody) Read(p []byte) (int, error) {
+	if b.idleTimer != nil {
+		b.mu.Lock()
+		b.reading = true
+		b.idleTimer.Reset(b.readIdle)
+		b.mu.Unlock()
+	}
+
+	n, err := b.body.Read(p)
+
+	b.mu.Lock()
+	if b.idleTimer != nil {
+		b.reading = false
+		b.idleTimer.Stop()
+		if n > 0 || err == nil {
+			// Data arrived, so the stream was not idle even if the timer fired meanwhile
+			b.idle = false
+		} else if err != io.EOF && b.idle {
+			err = &TimeoutError{Phase: TimeoutPhaseReadIdle, Limit: b.readIdle}
+		}
+	}
+	first := false
+	if n > 0 {
+		if b.stats.Bytes == 0 {
+			b.stats.TimeToFirstByte = time.Since(b.start)
+			first = true
+		}
+		b.stats.Bytes += int64(n)
+	}
+	b.mu.Unlock()
+
+	if first {
+		for _, hook := range b.hooks.onFirstByte {
+			hook()
+		}
+	}
+
+	if err == io.EOF {
+		b.finish(nil)
+	} else if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+// Close closes the underlying body, runs the hooks if they have not run yet and releases the request context
+func (b *trackedBody) Close() error {
+	if b.idleTimer != nil {
+		b.mu.Lock()
+		b.idleTimer.Stop()
+		b.mu.Unlock()
+	}
+	err := b.body.Close()
+	b.finish(nil)
+	if b.cancel != nil {
+		b.cancel()
+	}
+	return err
+}
+
+// finish runs the hooks exactly once
+func (b *trackedBody) finish(err error) {
+	b.mu.Lock()
+	if b.done {
+		b.mu.Unlock()
+		return
+	}
+	b.done = true
+	b.stats.Duration = time.Since(b.start)
+	b.stats.Err = err
+	if b.stats.Bytes == 0 {
+		b.stats.TimeToFirstByte = b.stats.Duration
+	}
+	stats := b.stats
+	b.mu.Unlock()
+
+	for _, hook := range b.hooks.onDone {
+		hook(stats)
+	}
+}
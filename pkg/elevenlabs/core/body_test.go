@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newPipeBody returns a tracked body fed by the returned writer; the body fails once its context is canceled
+func newPipeBody(readIdle time.Duration) (*trackedBody, *io.PipeWriter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+	go func() {
+		<-ctx.Done()
+		r.CloseWithError(ctx.Err())
+	}()
+	return newTrackedBody(r, time.Now(), cancel, readIdle, bodyHooks{}), w
+}
+
+func TestTrackedBodyFailsIdleRead(t *testing.T) {
+	body, w := newPipeBody(20 * time.Millisecond)
+	defer w.Close()
+
+	_, err := body.Read(make([]byte, 8))
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Phase != TimeoutPhaseReadIdle {
+		t.Fatalf("expected a read-idle timeout, got %v", err)
+	}
+}
+
+func TestTrackedBodyAllowsSlowSteadyStream(t *testing.T) {
+	body, w := newPipeBody(30 * time.Millisecond)
+	go func() {
+		for i := 0; i < 10; i++ {
+			time.Sleep(5 * time.Millisecond)
+			w.Write([]byte("chunk"))
+		}
+		w.Close()
+	}()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("expected the stream to outlive the idle timeout while data flows, got %v", err)
+	}
+	if len(data) != 50 {
+		t.Errorf("expected 50 bytes, got %d", len(data))
+	}
+}
+
+func TestTrackedBodyConcurrentReadAndClose(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		body, w := newPipeBody(time.Millisecond)
+		stop := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-stop:
+					w.Close()
+					return
+				default:
+					w.Write([]byte("x"))
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			io.Copy(io.Discard, body)
+		}()
+		go func() {
+			defer wg.Done()
+			time.Sleep(2 * time.Millisecond)
+			body.Close()
+		}()
+		wg.Wait()
+		close(stop)
+	}
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+)
+
+// Cache stores generated audio by a key derived from everything that determines it. Text-to-speech requests
+// are served from the cache when it holds their key. See the cache package for memory and disk implementations.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored under key, or false if there is none or it has expired
+	Get(ctx context.Context, key string) (*CacheEntry, bool, error)
+	// Set stores the entry under key until ttl has passed; zero keeps it until it is evicted or invalidated
+	Set(ctx context.Context, key string, entry *CacheEntry, ttl time.Duration) error
+	// InvalidateVoice removes every entry generated with the voice
+	InvalidateVoice(ctx context.Context, voiceID string) error
+}
+
+// CacheEntry is a cached response body with the headers it was returned with
+type CacheEntry struct {
+	VoiceID string
+	Data    []byte
+	Header  http.Header
+}
+
+// Clone returns a copy of the entry that shares neither its data nor its header
+func (e *CacheEntry) Clone() *CacheEntry {
+	return &CacheEntry{
+		VoiceID: e.VoiceID,
+		Data:    bytes.Clone(e.Data),
+		Header:  e.Header.Clone(),
+	}
+}
+
+// WithoutCache sends a single request to the API even if the cache holds its response, and does not cache the result
+func WithoutCache() RequestOption {
+	return func(o *RequestOptions) {
+		o.SkipCache = true
+	}
+}
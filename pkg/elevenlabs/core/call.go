@@ -0,0 +1,122 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Do calls an API endpoint with the client's authentication, retries, middleware and logging, and decodes
+// the response into out.
+//
+// reqBody is sent as-is if it is an io.Reader or []byte and encoded as JSON otherwise; nil sends no body.
+// out may be nil to discard the response, a *[]byte or io.Writer to receive the raw body, or any value
+// the JSON response can be decoded into. Error responses are returned as *APIError.
+func (c *HTTPClient) Do(ctx context.Context, method, path string, reqBody, out interface{}, opts ...RequestOption) (*ResponseMetadata, error) {
+	body, headers, err := encodeBody(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	return c.call(ctx, method, path, body, headers, out, opts)
+}
+
+// DoStream calls an API endpoint like Do but returns the response with its body unread, for endpoints
+// that stream audio or events. Streams are not retried. The caller must close the response body.
+func (c *HTTPClient) DoStream(ctx context.Context, method, path string, reqBody interface{}, opts ...RequestOption) (*http.Response, error) {
+	body, headers, err := encodeBody(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Stream(ctx, method, path, body, headers, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, ParseAPIError(resp)
+	}
+	return resp, nil
+}
+
+// DoMultipart calls an API endpoint with a multipart form of files and fields, such as an upload of
+// audio samples, and decodes the response into out like Do
+func (c *HTTPClient) DoMultipart(ctx context.Context, method, path string, files []FileUpload, fields map[string]string, out interface{}, opts ...RequestOption) (*ResponseMetadata, error) {
+	form, err := CreateMultipartRequest(files, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		"Content-Type": form.Header.Get("Content-Type"),
+	}
+	return c.call(ctx, method, path, form.Body, headers, out, opts)
+}
+
+// call sends a request and decodes its response into out
+func (c *HTTPClient) call(ctx context.Context, method, path string, body io.Reader, headers map[string]string, out interface{}, opts []RequestOption) (*ResponseMetadata, error) {
+	resp, err := c.Request(ctx, method, path, body, headers, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse(resp, out)
+}
+
+// encodeBody returns the reader and headers for a request body passed to Do
+func encodeBody(reqBody interface{}) (io.Reader, map[string]string, error) {
+	switch body := reqBody.(type) {
+	case nil:
+		return nil, nil, nil
+	case io.Reader:
+		return body, nil, nil
+	case []byte:
+		return bytes.NewReader(body), nil, nil
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		return bytes.NewReader(data), map[string]string{"Content-Type": "application/json"}, nil
+	}
+}
+
+// decodeResponse checks the status of a response and decodes its body into out
+func decodeResponse(resp *http.Response, out interface{}) (*ResponseMetadata, error) {
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, ParseAPIError(resp)
+	}
+
+	metadata := NewResponseMetadata(resp)
+	switch dst := out.(type) {
+	case nil:
+		_, err := io.Copy(io.Discard, resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+	case *[]byte:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		*dst = data
+	case io.Writer:
+		if _, err := io.Copy(dst, resp.Body); err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+	default:
+		// An empty body, as in a 204 response, leaves out unchanged
+		if err := json.NewDecoder(resp.Body).Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotModified is returned by a CatalogFetcher when the catalog has not changed since the given ETag
+var ErrNotModified = errors.New("not modified")
+
+// CatalogConfig configures the catalogs that cache lists fetched from the API
+type CatalogConfig struct {
+	// TTL is how long a list is served before it is refreshed in the background; zero keeps it until invalidated
+	TTL time.Duration
+}
+
+// CatalogFetcher fetches every item of a catalog. etag is the ETag of the items held, or "" if there are none;
+// a fetcher that supports conditional requests returns ErrNotModified if they are still current. It returns
+// the items with their new ETag, or "" if the API did not send one.
+type CatalogFetcher[T any] func(ctx context.Context, etag string) ([]T, string, error)
+
+// Catalog caches a list fetched from the API, such as the available voices, with lookup by ID and name.
+// The first lookup fetches the list. Once it is older than the TTL, lookups keep returning it while it is
+// refreshed in the background. A failed refresh leaves the stale list in place rather than failing lookups,
+// and is retried once the TTL has passed again.
+type Catalog[T any] struct {
+	fetch CatalogFetcher[T]
+	id    func(T) string
+	name  func(T) string
+	ttl   time.Duration
+	// clone copies an item handed to a caller, so callers cannot change the items held
+	clone func(T) T
+
+	mu          sync.Mutex
+	items       []T
+	byID        map[string]T
+	byName      map[string]T
+	etag        string
+	loaded      bool
+	fetchedAt   time.Time
+	invalidated bool
+	err         error
+	// generation counts invalidations; fetched is the generation the last completed fetch started in
+	generation int
+	fetched    int
+	refreshing chan struct{}
+}
+
+// NewCatalog creates a catalog that fetches its items with fetch and keeps them fresh for ttl; zero or less
+// keeps them until invalidated. id and name return the keys an item is looked up by.
+func NewCatalog[T any](ttl time.Duration, fetch CatalogFetcher[T], id, name func(T) string) *Catalog[T] {
+	return &Catalog[T]{
+		fetch: fetch,
+		id:    id,
+		name:  name,
+		ttl:   ttl,
+	}
+}
+
+// SetClone sets the function that copies each item returned by lookups. Items holding maps, slices or pointers
+// need one to keep callers from changing the catalog; without it, items are returned as shallow copies.
+func (c *Catalog[T]) SetClone(clone func(T) T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clone = clone
+}
+
+// Get returns the item with the given ID, or false if there is none
+func (c *Catalog[T]) Get(ctx context.Context, id string) (T, bool, error) {
+	var zero T
+	if err := c.load(ctx); err != nil {
+		return zero, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.byID[id]
+	return c.copy(item), ok, nil
+}
+
+// GetByName returns the item with the given name, ignoring case, or false if there is none.
+// If several items share a name, the first is returned.
+func (c *Catalog[T]) GetByName(ctx context.Context, name string) (T, bool, error) {
+	var zero T
+	if err := c.load(ctx); err != nil {
+		return zero, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.byName[strings.ToLower(name)]
+	return c.copy(item), ok, nil
+}
+
+// All returns every item in the order the API listed them
+func (c *Catalog[T]) All(ctx context.Context) ([]T, error) {
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	items := make([]T, len(c.items))
+	for i, item := range c.items {
+		items[i] = c.copy(item)
+	}
+	return items, nil
+}
+
+// copy returns an item for a caller; the caller must hold the lock
+func (c *Catalog[T]) copy(item T) T {
+	if c.clone == nil {
+		return item
+	}
+	return c.clone(item)
+}
+
+// Refresh fetches the items now and returns the error of the fetch, if any. The items held are kept on failure.
+func (c *Catalog[T]) Refresh(ctx context.Context) error {
+	return c.refresh(ctx)
+}
+
+// Invalidate marks the items as outdated, so the next lookup waits for them to be fetched again
+func (c *Catalog[T]) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate()
+}
+
+// Remove drops the item with the given ID at once and invalidates the catalog
+func (c *Catalog[T]) Remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items := make([]T, 0, len(c.items))
+	for _, item := range c.items {
+		if c.id(item) != id {
+			items = append(items, item)
+		}
+	}
+	c.index(items)
+	c.invalidate()
+}
+
+// Err returns the error of the last fetch, or nil if it succeeded
+func (c *Catalog[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// load makes sure the items can be looked up. It waits for a fetch if there are no items yet or they were
+// invalidated, and starts one in the background if they are older than the TTL.
+func (c *Catalog[T]) load(ctx context.Context) error {
+	c.mu.Lock()
+	loaded, invalidated := c.loaded, c.invalidated
+	expired := c.ttl > 0 && time.Since(c.fetchedAt) > c.ttl && c.refreshing == nil
+	c.mu.Unlock()
+
+	switch {
+	case !loaded:
+		return c.refresh(ctx)
+	case invalidated:
+		// Serve the stale items if they cannot be fetched
+		c.refresh(ctx)
+	case expired:
+		// The refresh outlives the lookup that started it
+		go c.refresh(context.WithoutCancel(ctx))
+	}
+	return nil
+}
+
+// refresh fetches the items, or waits for the fetch already in progress if it started after the last invalidation
+func (c *Catalog[T]) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	generation := c.generation
+	for c.refreshing != nil {
+		done := c.refreshing
+		c.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		c.mu.Lock()
+		if c.fetched >= generation {
+			err := c.err
+			c.mu.Unlock()
+			return err
+		}
+	}
+	done := make(chan struct{})
+	c.refreshing = done
+	etag := c.etag
+	c.mu.Unlock()
+
+	items, newETag, err := c.fetch(ctx, etag)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(done)
+	c.refreshing = nil
+	c.fetched = generation
+	notModified := errors.Is(err, ErrNotModified)
+	if notModified {
+		err = nil
+	}
+	c.err = err
+
+	// Items fetched before an invalidation may already be outdated
+	if generation != c.generation || !c.loaded && err != nil {
+		return err
+	}
+	if err == nil && !notModified {
+		c.index(items)
+		c.etag = newETag
+		c.loaded = true
+	}
+	// After a failure the stale items are served until the TTL has passed again
+	c.fetchedAt = time.Now()
+	c.invalidated = false
+	return err
+}
+
+// invalidate marks the items as outdated; the caller must hold the lock.
+// The ETag is dropped so that the next fetch returns the items in full.
+func (c *Catalog[T]) invalidate() {
+	c.invalidated = true
+	c.etag = ""
+	c.generation++
+}
+
+// index replaces the items and their lookup tables; the caller must hold the lock
+func (c *Catalog[T]) index(items []T) {
+	c.items = items
+	c.byID = make(map[string]T, len(items))
+	c.byName = make(map[string]T, len(items))
+	for _, item := range items {
+		c.byID[c.id(item)] = item
+		if key := strings.ToLower(c.name(item)); key != "" {
+			if _, ok := c.byName[key]; !ok {
+				c.byName[key] = item
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type catalogItem struct {
+	ID   string
+	Name string
+}
+
+// newTestCatalog returns a catalog of catalogItem fetched with fetch
+func newTestCatalog(ttl time.Duration, fetch CatalogFetcher[catalogItem]) *Catalog[catalogItem] {
+	return NewCatalog(ttl, fetch,
+		func(item catalogItem) string { return item.ID },
+		func(item catalogItem) string { return item.Name },
+	)
+}
+
+func TestCatalogLooksUpByIDAndName(t *testing.T) {
+	var fetches atomic.Int32
+	c := newTestCatalog(0, func(ctx context.Context, etag string) ([]catalogItem, string, error) {
+		fetches.Add(1)
+		return []catalogItem{{"1", "Rachel"}, {"2", "Adam"}, {"3", "rachel"}}, "", nil
+	})
+	ctx := context.Background()
+
+	if item, ok, err := c.Get(ctx, "2"); err != nil || !ok || item.Name != "Adam" {
+		t.Errorf("expected Adam, got %+v, %v, %v", item, ok, err)
+	}
+	if item, ok, _ := c.GetByName(ctx, "RACHEL"); !ok || item.ID != "1" {
+		t.Errorf("expected the first item named Rachel, got %+v", item)
+	}
+	if _, ok, _ := c.Get(ctx, "4"); ok {
+		t.Error("expected no item with ID 4")
+	}
+	if items, _ := c.All(ctx); len(items) != 3 {
+		t.Errorf("expected 3 items, got %v", items)
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("expected a single fetch, got %d", got)
+	}
+}
+
+func TestCatalogServesStaleItemsWhileRefreshing(t *testing.T) {
+	var fetches atomic.Int32
+	refreshed := make(chan struct{})
+	c := newTestCatalog(10*time.Millisecond, func(ctx context.Context, etag string) ([]catalogItem, string, error) {
+		switch fetches.Add(1) {
+		case 1:
+			return []catalogItem{{"1", "old"}}, "v1", nil
+		case 2:
+			if etag != "v1" {
+				t.Errorf("expected the refresh to send the ETag, got %q", etag)
+			}
+			defer close(refreshed)
+			return nil, "v1", ErrNotModified
+		default:
+			return []catalogItem{{"1", "new"}}, "v2", nil
+		}
+	})
+	ctx := context.Background()
+
+	c.Get(ctx, "1")
+	time.Sleep(20 * time.Millisecond)
+	if item, _, _ := c.Get(ctx, "1"); item.Name != "old" {
+		t.Errorf("expected the stale item during the refresh, got %+v", item)
+	}
+	<-refreshed
+
+	// An invalidated catalog waits for the full list
+	c.Invalidate()
+	if item, _, _ := c.Get(ctx, "1"); item.Name != "new" {
+		t.Errorf("expected the fetched item after invalidation, got %+v", item)
+	}
+}
+
+func TestCatalogKeepsItemsWhenRefreshFails(t *testing.T) {
+	fetchErr := errors.New("unavailable")
+	var fail atomic.Bool
+	c := newTestCatalog(0, func(ctx context.Context, etag string) ([]catalogItem, string, error) {
+		if fail.Load() {
+			return nil, "", fetchErr
+		}
+		return []catalogItem{{"1", "Rachel"}}, "", nil
+	})
+	ctx := context.Background()
+	c.Get(ctx, "1")
+
+	fail.Store(true)
+	if err := c.Refresh(ctx); !errors.Is(err, fetchErr) {
+		t.Fatalf("expected the fetch error, got %v", err)
+	}
+	if _, ok, err := c.Get(ctx, "1"); !ok || err != nil {
+		t.Errorf("expected the previous items after a failed refresh, got %v, %v", ok, err)
+	}
+	if !errors.Is(c.Err(), fetchErr) {
+		t.Errorf("expected Err to report the failed refresh, got %v", c.Err())
+	}
+
+	c.Remove("1")
+	if _, ok, _ := c.Get(ctx, "1"); ok {
+		t.Error("expected the removed item to be gone even though the refresh fails")
+	}
+}
+
+func TestCatalogFirstFetchError(t *testing.T) {
+	fetchErr := errors.New("unavailable")
+	c := newTestCatalog(0, func(ctx context.Context, etag string) ([]catalogItem, string, error) {
+		return nil, "", fetchErr
+	})
+	if _, _, err := c.Get(context.Background(), "1"); !errors.Is(err, fetchErr) {
+		t.Errorf("expected the fetch error, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is when a request was rejected by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned instead of sending a request while the circuit for its endpoint family is open
+type CircuitOpenError struct {
+	// Family is the endpoint family whose circuit is open
+	Family string
+	// RetryAfter is the remaining cooldown before a probe request is allowed
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for %s endpoints, retry in %s", e.Family, e.RetryAfter.Round(time.Millisecond))
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of the circuit for one endpoint family
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests until the cooldown has passed
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through to test whether the API has recovered
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Endpoint families used by the default circuit breaker scoping
+const (
+	FamilyTextToSpeech = "tts"
+	FamilyStreaming    = "streaming"
+	FamilyVoices       = "voices"
+)
+
+// CircuitBreakerConfig configures a CircuitBreaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive 5xx responses or transport errors that opens the circuit
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a probe request is allowed
+	Cooldown time.Duration
+	// Family maps a request to the endpoint family it is counted against; defaults to EndpointFamily
+	Family func(req *http.Request) string
+	// OnStateChange, if set, is called whenever the circuit of a family changes state. It may use the breaker.
+	OnStateChange func(family string, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerConfig returns the default circuit breaker configuration
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// CircuitBreaker fails requests fast after sustained API failures, tracking each endpoint family separately
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// stateChange is a transition reported to OnStateChange once the lock is released
+type stateChange struct {
+	family   string
+	from, to CircuitState
+}
+
+// circuit is the breaker state of one endpoint family
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probeAt  time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker, using defaults for unset fields
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerConfig()
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+	if config.Family == nil {
+		config.Family = func(req *http.Request) string {
+			return EndpointFamily(req.URL.Path)
+		}
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// State returns the current state of the circuit for an endpoint family
+func (b *CircuitBreaker) State(family string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[family]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= b.config.Cooldown {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Reset closes the circuit of every endpoint family
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.circuits = make(map[string]*circuit)
+}
+
+// Allow returns a *CircuitOpenError if a request to the family must not be sent.
+// Once the cooldown has passed, one probe request at a time is allowed.
+func (b *CircuitBreaker) Allow(family string) error {
+	b.mu.Lock()
+	change, err := b.allow(family)
+	b.mu.Unlock()
+
+	b.notify(change)
+	return err
+}
+
+// allow decides whether a request may be sent; the caller must hold the lock
+func (b *CircuitBreaker) allow(family string) (*stateChange, error) {
+	c, ok := b.circuits[family]
+	if !ok || c.state == CircuitClosed {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if c.state == CircuitOpen {
+		if remaining := b.config.Cooldown - now.Sub(c.openedAt); remaining > 0 {
+			return nil, &CircuitOpenError{Family: family, RetryAfter: remaining}
+		}
+		c.probeAt = now
+		return b.transition(family, c, CircuitHalfOpen), nil
+	}
+
+	// Half-open: a probe that never reported back is replaced after another cooldown
+	if now.Sub(c.probeAt) < b.config.Cooldown {
+		return nil, &CircuitOpenError{Family: family, RetryAfter: b.config.Cooldown - now.Sub(c.probeAt)}
+	}
+	c.probeAt = now
+	return nil, nil
+}
+
+// Record reports the outcome of a request to the family
+func (b *CircuitBreaker) Record(family string, success bool) {
+	b.mu.Lock()
+	change := b.record(family, success)
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// record updates the circuit of a family with an outcome; the caller must hold the lock
+func (b *CircuitBreaker) record(family string, success bool) *stateChange {
+	c, ok := b.circuits[family]
+	if !ok {
+		if success {
+			return nil
+		}
+		c = &circuit{}
+		b.circuits[family] = c
+	}
+
+	if success {
+		c.failures = 0
+		if c.state != CircuitClosed {
+			return b.transition(family, c, CircuitClosed)
+		}
+		return nil
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= b.config.FailureThreshold) {
+		c.openedAt = time.Now()
+		return b.transition(family, c, CircuitOpen)
+	}
+	return nil
+}
+
+// family returns the endpoint family of a request
+func (b *CircuitBreaker) family(req *http.Request) string {
+	return b.config.Family(req)
+}
+
+// transition changes the state of a circuit and returns the change to report; the caller must hold the lock
+func (b *CircuitBreaker) transition(family string, c *circuit, to CircuitState) *stateChange {
+	from := c.state
+	c.state = to
+	return &stateChange{family: family, from: from, to: to}
+}
+
+// notify calls OnStateChange for a transition. It is called without the lock, so the callback may use the breaker.
+func (b *CircuitBreaker) notify(change *stateChange) {
+	if change != nil && b.config.OnStateChange != nil {
+		b.config.OnStateChange(change.family, change.from, change.to)
+	}
+}
+
+// isCircuitFailure reports whether an attempt outcome counts against the circuit
+func isCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// EndpointFamily groups an API path into the family used for circuit breaking:
+// streaming text-to-speech, other text-to-speech, voices, or the first path segment after the version
+func EndpointFamily(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && len(segments[0]) > 1 && segments[0][0] == 'v' && segments[0][1] >= '0' && segments[0][1] <= '9' {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		return "default"
+	}
+
+	switch segments[0] {
+	case "text-to-speech":
+		last := segments[len(segments)-1]
+		if last == "stream" || last == "stream-input" || last == "stream-with-timestamps" || (len(segments) > 1 && segments[len(segments)-2] == "stream") {
+			return FamilyStreaming
+		}
+		return FamilyTextToSpeech
+	case "voices":
+		return FamilyVoices
+	default:
+		return segments[0]
+	}
+}
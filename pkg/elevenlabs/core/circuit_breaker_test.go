@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Cooldown: 50 * time.Millisecond})
+
+	b.Record(FamilyTextToSpeech, false)
+	if err := b.Allow(FamilyTextToSpeech); err != nil {
+		t.Fatalf("expected the circuit to stay closed below the threshold, got %v", err)
+	}
+	b.Record(FamilyTextToSpeech, false)
+
+	err := b.Allow(FamilyTextToSpeech)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	if openErr.Family != FamilyTextToSpeech || openErr.RetryAfter <= 0 {
+		t.Errorf("unexpected error %+v", openErr)
+	}
+	if err := b.Allow(FamilyVoices); err != nil {
+		t.Errorf("expected other families to be unaffected, got %v", err)
+	}
+}
+
+func TestCircuitBreakerProbesAfterCooldown(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Cooldown: 20 * time.Millisecond})
+	b.Record(FamilyTextToSpeech, false)
+
+	time.Sleep(30 * time.Millisecond)
+	if got := b.State(FamilyTextToSpeech); got != CircuitHalfOpen {
+		t.Fatalf("expected half-open after the cooldown, got %s", got)
+	}
+	if err := b.Allow(FamilyTextToSpeech); err != nil {
+		t.Fatalf("expected a probe to be allowed, got %v", err)
+	}
+	if err := b.Allow(FamilyTextToSpeech); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a single probe at a time, got %v", err)
+	}
+
+	// A failed probe opens the circuit again
+	b.Record(FamilyTextToSpeech, false)
+	if got := b.State(FamilyTextToSpeech); got != CircuitOpen {
+		t.Fatalf("expected the failed probe to open the circuit, got %s", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := b.Allow(FamilyTextToSpeech); err != nil {
+		t.Fatal(err)
+	}
+	b.Record(FamilyTextToSpeech, true)
+	if got := b.State(FamilyTextToSpeech); got != CircuitClosed {
+		t.Fatalf("expected the successful probe to close the circuit, got %s", got)
+	}
+}
+
+func TestCircuitBreakerStateChangeMayUseBreaker(t *testing.T) {
+	var b *CircuitBreaker
+	var changes []string
+	b = NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Millisecond,
+		OnStateChange: func(family string, from, to CircuitState) {
+			changes = append(changes, from.String()+">"+b.State(family).String())
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Record(FamilyVoices, false)
+		time.Sleep(5 * time.Millisecond)
+		b.Allow(FamilyVoices)
+		b.Record(FamilyVoices, true)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("OnStateChange deadlocked")
+	}
+
+	want := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, changes)
+		}
+	}
+}
+
+func TestEndpointFamily(t *testing.T) {
+	tests := map[string]string{
+		"/v1/text-to-speech/voice":                        FamilyTextToSpeech,
+		"/v1/text-to-speech/voice/with-timestamps":        FamilyTextToSpeech,
+		"/v1/text-to-speech/voice/stream":                 FamilyStreaming,
+		"/v1/text-to-speech/voice/stream/with-timestamps": FamilyStreaming,
+		"/v1/text-to-speech/voice/stream-with-timestamps": FamilyStreaming,
+		"/v1/text-to-speech/voice/stream-input":           FamilyStreaming,
+		"/v1/voices/voice/settings":                       FamilyVoices,
+		"/v2/voices":                                      FamilyVoices,
+		"/v1/models":                                      "models",
+		"/":                                               "default",
+	}
+	for path, want := range tests {
+		if got := EndpointFamily(path); got != want {
+			t.Errorf("EndpointFamily(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRequestFailsFastWhileCircuitIsOpen(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, Config{CircuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Cooldown: time.Minute})})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Request(context.Background(), http.MethodGet, "v1/voices", nil, nil, WithMaxRetries(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := client.Request(context.Background(), http.MethodGet, "v1/voices", nil, nil, WithMaxRetries(0)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", got)
+	}
+}
@@ -0,0 +1,257 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned when a credential provider has no usable API key
+var ErrNoCredentials = errors.New("elevenlabs: no valid API key available")
+
+// maxCredentialRetries bounds how often a request rejected with 401 is resent with another key
+const maxCredentialRetries = 3
+
+// DefaultAPIKeyEnv is the environment variable read by EnvCredentialProvider by default
+const DefaultAPIKeyEnv = "ELEVENLABS_API_KEY"
+
+// CredentialProvider supplies the API key for each request and WebSocket dial.
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialInvalidator is implemented by credential providers that can stop handing out a key
+// after the API rejected it with 401 Unauthorized
+type CredentialInvalidator interface {
+	Invalidate(apiKey string)
+}
+
+// QuotaTracker is implemented by credential providers that track the remaining character quota of their keys
+type QuotaTracker interface {
+	// QuotaExceeded is called when the API rejected a key because its character quota is used up
+	QuotaExceeded(apiKey string)
+	// RecordUsage is called with the number of characters billed to a key
+	RecordUsage(apiKey string, characters int)
+}
+
+// StaticCredentialProvider always returns the same API key. An empty key sends requests unauthenticated.
+type StaticCredentialProvider struct {
+	apiKey string
+}
+
+// NewStaticCredentialProvider creates a provider for a fixed API key
+func NewStaticCredentialProvider(apiKey string) *StaticCredentialProvider {
+	return &StaticCredentialProvider{apiKey: apiKey}
+}
+
+// APIKey implements CredentialProvider
+func (p *StaticCredentialProvider) APIKey(ctx context.Context) (string, error) {
+	return p.apiKey, nil
+}
+
+// EnvCredentialProvider reads the API key from an environment variable on every request
+type EnvCredentialProvider struct {
+	name string
+}
+
+// NewEnvCredentialProvider creates a provider reading the named variable, or ELEVENLABS_API_KEY if name is empty
+func NewEnvCredentialProvider(name string) *EnvCredentialProvider {
+	if name == "" {
+		name = DefaultAPIKeyEnv
+	}
+	return &EnvCredentialProvider{name: name}
+}
+
+// APIKey implements CredentialProvider
+func (p *EnvCredentialProvider) APIKey(ctx context.Context) (string, error) {
+	apiKey := strings.TrimSpace(os.Getenv(p.name))
+	if apiKey == "" {
+		return "", fmt.Errorf("%w: %s is not set", ErrNoCredentials, p.name)
+	}
+	return apiKey, nil
+}
+
+// FileCredentialProvider reads the API key from a file and picks up changes to it,
+// so keys can be rotated by rewriting the file, for example from a mounted secret
+type FileCredentialProvider struct {
+	path     string
+	interval time.Duration
+
+	mu        sync.Mutex
+	apiKey    string
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewFileCredentialProvider creates a provider that checks the file for changes at most once per interval
+// (10 seconds if interval is 0)
+func NewFileCredentialProvider(path string, interval time.Duration) *FileCredentialProvider {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &FileCredentialProvider{path: path, interval: interval}
+}
+
+// APIKey implements CredentialProvider
+func (p *FileCredentialProvider) APIKey(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.apiKey == "" || time.Since(p.checkedAt) >= p.interval {
+		if err := p.reload(); err != nil {
+			return "", err
+		}
+	}
+	return p.apiKey, nil
+}
+
+// Invalidate implements CredentialInvalidator by re-reading the file on the next request
+func (p *FileCredentialProvider) Invalidate(apiKey string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.apiKey == apiKey {
+		p.checkedAt = time.Time{}
+		p.modTime = time.Time{}
+	}
+}
+
+// reload reads the file if it changed since it was last read; the caller must hold the lock
+func (p *FileCredentialProvider) reload() error {
+	p.checkedAt = time.Now()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+	if p.apiKey != "" && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+	apiKey := strings.TrimSpace(string(data))
+	if apiKey == "" {
+		return fmt.Errorf("%w: %s is empty", ErrNoCredentials, p.path)
+	}
+
+	p.apiKey = apiKey
+	p.modTime = info.ModTime()
+	return nil
+}
+
+// RoundRobinCredentialProvider spreads requests over several API keys, skipping keys that were invalidated
+type RoundRobinCredentialProvider struct {
+	mu      sync.Mutex
+	keys    []string
+	invalid map[string]bool
+	next    int
+}
+
+// NewRoundRobinCredentialProvider creates a provider cycling through the given keys
+func NewRoundRobinCredentialProvider(keys ...string) *RoundRobinCredentialProvider {
+	return &RoundRobinCredentialProvider{
+		keys:    append([]string(nil), keys...),
+		invalid: make(map[string]bool),
+	}
+}
+
+// APIKey implements CredentialProvider
+func (p *RoundRobinCredentialProvider) APIKey(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(p.keys); i++ {
+		apiKey := p.keys[p.next%len(p.keys)]
+		p.next = (p.next + 1) % len(p.keys)
+		if !p.invalid[apiKey] {
+			return apiKey, nil
+		}
+	}
+	return "", ErrNoCredentials
+}
+
+// Invalidate implements CredentialInvalidator by removing the key from the rotation
+func (p *RoundRobinCredentialProvider) Invalidate(apiKey string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.invalid[apiKey] = true
+}
+
+// Restore puts an invalidated key back into the rotation
+func (p *RoundRobinCredentialProvider) Restore(apiKey string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.invalid, apiKey)
+}
+
+// invalidateCredentials tells the provider that the API rejected a key, if it supports invalidation
+func invalidateCredentials(provider CredentialProvider, apiKey string) {
+	if invalidator, ok := provider.(CredentialInvalidator); ok && apiKey != "" {
+		invalidator.Invalidate(apiKey)
+	}
+}
+
+// rejectCredentials reports whether the response rejected the API key rather than the request, telling the
+// provider about invalid keys and exhausted quotas. Rejection bodies are read and replaced so callers can still parse them.
+func rejectCredentials(provider CredentialProvider, apiKey string, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+	default:
+		return false
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body = readCloser{Reader: bytes.NewReader(data), Closer: resp.Body}
+	if err != nil {
+		return false
+	}
+
+	var errorResp ErrorResponse
+	json.Unmarshal(data, &errorResp)
+
+	switch code := errorResp.code(); {
+	case code == CodeQuotaExceeded:
+		if tracker, ok := provider.(QuotaTracker); ok {
+			tracker.QuotaExceeded(apiKey)
+		} else {
+			invalidateCredentials(provider, apiKey)
+		}
+		return true
+	case code == CodeInvalidAPIKey || (code == "" && resp.StatusCode == http.StatusUnauthorized):
+		invalidateCredentials(provider, apiKey)
+		return true
+	default:
+		return false
+	}
+}
+
+// recordUsage charges the character cost of a successful response to the key that made it
+func recordUsage(provider CredentialProvider, apiKey string, resp *http.Response) {
+	tracker, ok := provider.(QuotaTracker)
+	if !ok || apiKey == "" {
+		return
+	}
+	if cost := NewResponseMetadata(resp).CharacterCost; cost > 0 {
+		tracker.RecordUsage(apiKey, cost)
+	}
+}
+
+// readCloser combines a reader with the closer of the body it replaces
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
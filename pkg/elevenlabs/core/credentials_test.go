@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRoundRobinCredentialProvider(t *testing.T) {
+	p := NewRoundRobinCredentialProvider("a", "b", "c")
+	ctx := context.Background()
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		key, _ := p.APIKey(ctx)
+		got = append(got, key)
+	}
+	if want := []string{"a", "b", "c", "a"}; !equalStrings(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	p.Invalidate("b")
+	p.Invalidate("c")
+	for i := 0; i < 2; i++ {
+		if key, _ := p.APIKey(ctx); key != "a" {
+			t.Fatalf("expected invalidated keys to be skipped, got %q", key)
+		}
+	}
+
+	p.Invalidate("a")
+	if _, err := p.APIKey(ctx); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+	p.Restore("b")
+	if key, _ := p.APIKey(ctx); key != "b" {
+		t.Fatalf("expected the restored key, got %q", key)
+	}
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	t.Setenv("TEST_ELEVENLABS_KEY", " env-key \n")
+	if key, err := NewEnvCredentialProvider("TEST_ELEVENLABS_KEY").APIKey(context.Background()); err != nil || key != "env-key" {
+		t.Fatalf("expected env-key, got %q, %v", key, err)
+	}
+
+	t.Setenv("TEST_ELEVENLABS_KEY", "")
+	if _, err := NewEnvCredentialProvider("TEST_ELEVENLABS_KEY").APIKey(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestFileCredentialProviderPicksUpRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("old-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewFileCredentialProvider(path, time.Hour)
+	if key, err := p.APIKey(context.Background()); err != nil || key != "old-key" {
+		t.Fatalf("expected old-key, got %q, %v", key, err)
+	}
+
+	if err := os.WriteFile(path, []byte("new-key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := p.APIKey(context.Background()); key != "old-key" {
+		t.Fatalf("expected the key to be cached until the interval passes, got %q", key)
+	}
+
+	// A rejected key makes the provider read the file again
+	p.Invalidate("old-key")
+	if key, _ := p.APIKey(context.Background()); key != "new-key" {
+		t.Fatalf("expected new-key after invalidation, got %q", key)
+	}
+}
+
+func TestRequestRetriesWithAnotherKeyAfter401(t *testing.T) {
+	provider := NewRoundRobinCredentialProvider("revoked-key", "valid-key")
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(APIKeyHeader) != "valid-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}, Config{Credentials: provider})
+
+	resp, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil, WithMaxRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// The revoked key is out of the rotation
+	for i := 0; i < 2; i++ {
+		if key, _ := provider.APIKey(context.Background()); key != "valid-key" {
+			t.Fatalf("expected only valid-key, got %q", key)
+		}
+	}
+}
+
+// equalStrings reports whether two slices hold the same strings in the same order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
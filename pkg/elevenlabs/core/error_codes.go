@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Error codes sent by the API in detail.status
+const (
+	CodeQuotaExceeded             = "quota_exceeded"
+	CodeVoiceNotFound             = "voice_not_found"
+	CodeTooManyConcurrentRequests = "too_many_concurrent_requests"
+	CodeSystemBusy                = "system_busy"
+	CodeInvalidAPIKey             = "invalid_api_key"
+	CodeMaxCharacterLimitExceeded = "max_character_limit_exceeded"
+)
+
+// Sentinel errors matched by errors.Is against API errors carrying the corresponding error code
+var (
+	ErrQuotaExceeded             = errors.New("elevenlabs: quota exceeded")
+	ErrVoiceNotFound             = errors.New("elevenlabs: voice not found")
+	ErrTooManyConcurrentRequests = errors.New("elevenlabs: too many concurrent requests")
+	ErrSystemBusy                = errors.New("elevenlabs: system busy")
+	ErrInvalidAPIKey             = errors.New("elevenlabs: invalid API key")
+	ErrMaxCharacterLimitExceeded = errors.New("elevenlabs: max character limit exceeded")
+)
+
+// codeErrors maps error codes to their sentinel errors
+var codeErrors = map[string]error{
+	CodeQuotaExceeded:             ErrQuotaExceeded,
+	CodeVoiceNotFound:             ErrVoiceNotFound,
+	CodeTooManyConcurrentRequests: ErrTooManyConcurrentRequests,
+	CodeSystemBusy:                ErrSystemBusy,
+	CodeInvalidAPIKey:             ErrInvalidAPIKey,
+	CodeMaxCharacterLimitExceeded: ErrMaxCharacterLimitExceeded,
+}
+
+// ErrorCode returns the error code of the API error in err's chain, or "" if there is none
+func ErrorCode(err error) string {
+	var apiErr ElevenLabsError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code()
+	}
+	return ""
+}
+
+// IsQuotaExceeded reports whether err was caused by the character quota of the API key being used up
+func IsQuotaExceeded(err error) bool {
+	return errors.Is(err, ErrQuotaExceeded)
+}
+
+// IsVoiceNotFound reports whether err was caused by an unknown voice ID
+func IsVoiceNotFound(err error) bool {
+	return errors.Is(err, ErrVoiceNotFound)
+}
+
+// IsTooManyConcurrentRequests reports whether err was caused by exceeding the concurrency limit of the subscription
+func IsTooManyConcurrentRequests(err error) bool {
+	return errors.Is(err, ErrTooManyConcurrentRequests)
+}
+
+// IsInvalidAPIKey reports whether err was caused by a missing or invalid API key
+func IsInvalidAPIKey(err error) bool {
+	return errors.Is(err, ErrInvalidAPIKey)
+}
+
+// IsMaxCharacterLimitExceeded reports whether err was caused by submitting too much text in one request
+func IsMaxCharacterLimitExceeded(err error) bool {
+	return errors.Is(err, ErrMaxCharacterLimitExceeded)
+}
+
+// IsCredentialError reports whether err is tied to the API key rather than the request,
+// so the request may succeed with another key: invalid keys, exhausted quotas and 401 responses
+func IsCredentialError(err error) bool {
+	if IsInvalidAPIKey(err) || IsQuotaExceeded(err) {
+		return true
+	}
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsRequestError reports whether err was caused by the request itself and will recur until it is fixed
+func IsRequestError(err error) bool {
+	if IsVoiceNotFound(err) || IsMaxCharacterLimitExceeded(err) {
+		return true
+	}
+	switch statusCode(err) {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether the request may succeed if tried again later with the same key:
+// rate limits, busy or failing servers, open circuits and network errors
+func IsRetryable(err error) bool {
+	if err == nil || IsCredentialError(err) || IsRequestError(err) {
+		return false
+	}
+	if IsTooManyConcurrentRequests(err) || errors.Is(err, ErrSystemBusy) || errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	if code := statusCode(err); code > 0 {
+		return code == http.StatusRequestTimeout || code == http.StatusTooEarly ||
+			code == http.StatusTooManyRequests || code >= 500
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
+
+// statusCode returns the HTTP status code of the API error in err's chain, or 0 if there is none
+func statusCode(err error) int {
+	var apiErr ElevenLabsError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode()
+	}
+	return 0
+}
@@ -0,0 +1,368 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ElevenLabsError represents an error from the ElevenLabs API
+type ElevenLabsError interface {
+	error
+	StatusCode() int
+	Body() interface{}
+	// Code returns the machine-readable detail.status of the response, such as "quota_exceeded", or ""
+	Code() string
+}
+
+// APIError represents a generic API error
+type APIError struct {
+	statusCode int
+	code       string
+	body       interface{}
+	message    string
+}
+
+// NewAPIError creates a generic API error
+func NewAPIError(statusCode int, body interface{}, message string) *APIError {
+	return &APIError{
+		statusCode: statusCode,
+		body:       body,
+		message:    message,
+	}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	return fmt.Sprintf("API error with status code %d", e.statusCode)
+}
+
+// StatusCode returns the HTTP status code
+func (e *APIError) StatusCode() int {
+	return e.statusCode
+}
+
+// Body returns the error response body
+func (e *APIError) Body() interface{} {
+	return e.body
+}
+
+// Code returns the machine-readable error code of the response, or "" if it had none
+func (e *APIError) Code() string {
+	return e.code
+}
+
+// Is reports whether target is the sentinel error for the error code
+func (e *APIError) Is(target error) bool {
+	sentinel, ok := codeErrors[e.code]
+	return ok && sentinel == target
+}
+
+// BadRequestError represents a 400 Bad Request error
+type BadRequestError struct {
+	*APIError
+}
+
+// NewBadRequestError creates a new BadRequestError
+func NewBadRequestError(body interface{}) *BadRequestError {
+	return &BadRequestError{APIError: newStatusError(http.StatusBadRequest, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *BadRequestError) Unwrap() error {
+	return e.APIError
+}
+
+// UnauthorizedError represents a 401 Unauthorized error, returned for missing or invalid API keys
+type UnauthorizedError struct {
+	*APIError
+}
+
+// NewUnauthorizedError creates a new UnauthorizedError
+func NewUnauthorizedError(body interface{}) *UnauthorizedError {
+	return &UnauthorizedError{APIError: newStatusError(http.StatusUnauthorized, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *UnauthorizedError) Unwrap() error {
+	return e.APIError
+}
+
+// ForbiddenError represents a 403 Forbidden error
+type ForbiddenError struct {
+	*APIError
+}
+
+// NewForbiddenError creates a new ForbiddenError
+func NewForbiddenError(body interface{}) *ForbiddenError {
+	return &ForbiddenError{APIError: newStatusError(http.StatusForbidden, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *ForbiddenError) Unwrap() error {
+	return e.APIError
+}
+
+// NotFoundError represents a 404 Not Found error
+type NotFoundError struct {
+	*APIError
+}
+
+// NewNotFoundError creates a new NotFoundError
+func NewNotFoundError(body interface{}) *NotFoundError {
+	return &NotFoundError{APIError: newStatusError(http.StatusNotFound, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *NotFoundError) Unwrap() error {
+	return e.APIError
+}
+
+// ValidationErrorDetail is one entry of the detail list of a 422 response
+type ValidationErrorDetail struct {
+	// Loc is the location of the invalid value, such as ["body", "voice_settings", "stability"]
+	Loc  []interface{} `json:"loc"`
+	Msg  string        `json:"msg"`
+	Type string        `json:"type"`
+}
+
+// Field returns the location of the invalid value joined with dots, without the leading "body" or "query"
+func (d ValidationErrorDetail) Field() string {
+	parts := make([]string, 0, len(d.Loc))
+	for i, loc := range d.Loc {
+		s := fmt.Sprint(loc)
+		if i == 0 && (s == "body" || s == "query" || s == "path" || s == "header") {
+			continue
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ".")
+}
+
+// UnprocessableEntityError represents a 422 Unprocessable Entity error
+type UnprocessableEntityError struct {
+	*APIError
+	// Details lists the validation failures reported by the API
+	Details []ValidationErrorDetail
+}
+
+// NewUnprocessableEntityError creates a new UnprocessableEntityError
+func NewUnprocessableEntityError(body interface{}) *UnprocessableEntityError {
+	return &UnprocessableEntityError{APIError: newStatusError(http.StatusUnprocessableEntity, body)}
+}
+
+// Error lists the validation failures after the status
+func (e *UnprocessableEntityError) Error() string {
+	if len(e.Details) == 0 {
+		return e.APIError.Error()
+	}
+
+	parts := make([]string, len(e.Details))
+	for i, detail := range e.Details {
+		if field := detail.Field(); field != "" {
+			parts[i] = field + ": " + detail.Msg
+		} else {
+			parts[i] = detail.Msg
+		}
+	}
+	return http.StatusText(http.StatusUnprocessableEntity) + ": " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *UnprocessableEntityError) Unwrap() error {
+	return e.APIError
+}
+
+// TooEarlyError represents a 425 Too Early error
+type TooEarlyError struct {
+	*APIError
+}
+
+// NewTooEarlyError creates a new TooEarlyError
+func NewTooEarlyError(body interface{}) *TooEarlyError {
+	return &TooEarlyError{APIError: newStatusError(http.StatusTooEarly, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *TooEarlyError) Unwrap() error {
+	return e.APIError
+}
+
+// TooManyRequestsError represents a 429 Too Many Requests error
+type TooManyRequestsError struct {
+	*APIError
+	// RetryAfter is the delay requested by the Retry-After header, or 0 if it was absent
+	RetryAfter time.Duration
+}
+
+// NewTooManyRequestsError creates a new TooManyRequestsError
+func NewTooManyRequestsError(body interface{}) *TooManyRequestsError {
+	return &TooManyRequestsError{APIError: newStatusError(http.StatusTooManyRequests, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *TooManyRequestsError) Unwrap() error {
+	return e.APIError
+}
+
+// ServerError represents a 5xx error returned by the API
+type ServerError struct {
+	*APIError
+}
+
+// NewServerError creates a new ServerError for the given 5xx status code
+func NewServerError(statusCode int, body interface{}) *ServerError {
+	return &ServerError{APIError: newStatusError(statusCode, body)}
+}
+
+// Unwrap returns the underlying APIError so errors.As can match it
+func (e *ServerError) Unwrap() error {
+	return e.APIError
+}
+
+// ErrorResponse represents a structured error response from the API
+type ErrorResponse struct {
+	Detail  *ErrorDetail `json:"detail,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// ErrorDetail is the detail field of an error response, which the API sends either as a
+// {"status", "message"} object, a list of validation errors or a plain string
+type ErrorDetail struct {
+	// Status is the machine-readable error code, such as "voice_not_found"
+	Status  string
+	Message string
+	// Errors lists the validation failures of a 422 response
+	Errors []ValidationErrorDetail
+	// Raw is the detail exactly as received
+	Raw json.RawMessage
+}
+
+// UnmarshalJSON decodes any of the detail shapes sent by the API
+func (d *ErrorDetail) UnmarshalJSON(data []byte) error {
+	d.Raw = append(json.RawMessage(nil), data...)
+
+	switch {
+	case json.Unmarshal(data, &d.Message) == nil:
+	case json.Unmarshal(data, &d.Errors) == nil:
+	default:
+		var detail struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+		// Unknown shapes are kept in Raw only
+		if json.Unmarshal(data, &detail) == nil {
+			d.Status = detail.Status
+			d.Message = detail.Message
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the detail as it was received
+func (d ErrorDetail) MarshalJSON() ([]byte, error) {
+	if len(d.Raw) > 0 {
+		return d.Raw, nil
+	}
+	if d.Errors != nil {
+		return json.Marshal(d.Errors)
+	}
+	return json.Marshal(struct {
+		Status  string `json:"status,omitempty"`
+		Message string `json:"message,omitempty"`
+	}{d.Status, d.Message})
+}
+
+// message returns the most specific error message of the response
+func (r ErrorResponse) message() string {
+	if r.Detail != nil && r.Detail.Message != "" {
+		return r.Detail.Message
+	}
+	if r.Error != "" {
+		return r.Error
+	}
+	return r.Message
+}
+
+// code returns the machine-readable error code of the response
+func (r ErrorResponse) code() string {
+	if r.Detail != nil {
+		return r.Detail.Status
+	}
+	return ""
+}
+
+// ParseAPIError parses an HTTP response and returns an appropriate error
+func ParseAPIError(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	// The error is typed by status first, so gateway errors with HTML or plain-text bodies are typed too
+	var errorResp ErrorResponse
+	var apiErr *APIError
+	body, err := io.ReadAll(resp.Body)
+	switch {
+	case err != nil:
+		apiErr = newStatusError(resp.StatusCode, nil)
+		apiErr.message += fmt.Sprintf(": failed to read error response: %v", err)
+	case json.Unmarshal(body, &errorResp) != nil:
+		// If we can't parse as JSON, use the raw body
+		apiErr = newStatusError(resp.StatusCode, string(body))
+		if raw := strings.TrimSpace(string(body)); raw != "" {
+			apiErr.message += ": " + raw
+		}
+	default:
+		apiErr = newStatusError(resp.StatusCode, errorResp)
+		apiErr.code = errorResp.code()
+		if message := errorResp.message(); message != "" {
+			apiErr.message += ": " + message
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		return &BadRequestError{APIError: apiErr}
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &UnauthorizedError{APIError: apiErr}
+	case resp.StatusCode == http.StatusForbidden:
+		return &ForbiddenError{APIError: apiErr}
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{APIError: apiErr}
+	case resp.StatusCode == http.StatusUnprocessableEntity:
+		var details []ValidationErrorDetail
+		if errorResp.Detail != nil {
+			details = errorResp.Detail.Errors
+		}
+		return &UnprocessableEntityError{APIError: apiErr, Details: details}
+	case resp.StatusCode == http.StatusTooEarly:
+		return &TooEarlyError{APIError: apiErr}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return &TooManyRequestsError{APIError: apiErr, RetryAfter: retryAfter}
+	case resp.StatusCode >= 500:
+		return &ServerError{APIError: apiErr}
+	default:
+		return apiErr
+	}
+}
+
+// newStatusError creates an APIError whose message is the status text
+func newStatusError(statusCode int, body interface{}) *APIError {
+	message := http.StatusText(statusCode)
+	if message == "" {
+		message = fmt.Sprintf("HTTP %d error", statusCode)
+	}
+	return &APIError{
+		statusCode: statusCode,
+		body:       body,
+		message:    message,
+	}
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// HedgePolicy trades cost for tail latency: when a request has not received the first byte of its response
+// body within Delay, a duplicate is sent, and the first response to start is used while the other requests are
+// canceled. Each duplicate is billed like the original, so hedging suits short, latency-critical requests.
+type HedgePolicy struct {
+	// Delay is how long to wait for the first byte of the response before sending the next duplicate;
+	// zero sends every duplicate at once
+	Delay time.Duration
+	// Alternates configure the duplicates in the order they are sent, for example to send them to another
+	// region or with another API key. An empty policy sends one duplicate of the original request.
+	Alternates []HedgeTarget
+}
+
+// HedgeTarget is where a duplicate request is sent. Empty fields keep the setting of the original request.
+type HedgeTarget struct {
+	// Environment sends the duplicate to the base URL of another environment, such as another region
+	Environment *Environment
+	// APIKey sends the duplicate with another API key
+	APIKey string
+}
+
+// HedgeMetrics describes a call sent with a hedging policy
+type HedgeMetrics struct {
+	Endpoint string
+	VoiceID  string
+	ModelID  string
+	// Hedges is the number of duplicates sent in addition to the original request
+	Hedges int
+	// Winner is the request whose response was returned: 0 for the original request and i for the
+	// duplicate sent to Alternates[i-1], or -1 if every request failed
+	Winner int
+	// Latency is the time until the first byte of the winning response arrived
+	Latency time.Duration
+	Err     error
+}
+
+// HedgeMetricsCollector is implemented by metrics collectors that also record hedged calls
+type HedgeMetricsCollector interface {
+	ObserveHedge(m HedgeMetrics)
+}
+
+// WithHedging sends a single request with the given hedging policy
+func WithHedging(policy HedgePolicy) RequestOption {
+	return func(o *RequestOptions) {
+		o.Hedge = &policy
+	}
+}
+
+// targets returns the targets of the duplicates to send
+func (p *HedgePolicy) targets() []HedgeTarget {
+	if len(p.Alternates) == 0 {
+		return []HedgeTarget{{}}
+	}
+	return p.Alternates
+}
+
+// hedgeResult is the outcome of one of the requests of a hedged call
+type hedgeResult struct {
+	leg     int
+	resp    *http.Response
+	retries int
+	err     error
+}
+
+// won reports whether the result can be returned as the response of the hedged call. Server errors and
+// responses rejecting the API key or its quota lose, since another request may use another key or region.
+func (r hedgeResult) won() bool {
+	if r.err != nil {
+		return false
+	}
+	switch r.resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return r.resp.StatusCode < http.StatusInternalServerError
+}
+
+// hedgedCall tracks the requests sent for one hedged call
+type hedgedCall struct {
+	results chan hedgeResult
+	cancels []context.CancelFunc
+	sent    int
+	pending int
+	start   time.Time
+}
+
+// requestHedged sends the request with requestWithRetry, sending duplicates as set by the hedging policy of
+// options, if any. Successful responses race on the first byte of their body. The first response without a
+// server or credential error is returned and the other requests are canceled; if every request fails, the
+// result of the first to fail is returned.
+func (c *HTTPClient) requestHedged(ctx context.Context, req *http.Request, settings retrySettings, options RequestOptions) (*http.Response, int, error) {
+	policy := options.Hedge
+	if policy == nil {
+		return c.requestWithRetry(ctx, req, settings)
+	}
+
+	targets := policy.targets()
+	call := &hedgedCall{
+		results: make(chan hedgeResult, len(targets)+1),
+		start:   time.Now(),
+	}
+
+	// launch sends the original request first and then the duplicate for each target in turn
+	launch := func() {
+		leg := call.sent
+		legCtx, cancel := context.WithCancel(ctx)
+		call.cancels = append(call.cancels, cancel)
+		call.sent++
+		call.pending++
+
+		legReq, err := rewindRequest(legCtx, req, 1)
+		legSettings := settings
+		if err == nil && leg > 0 {
+			target := targets[leg-1]
+			if target.Environment != nil {
+				err = retarget(legReq, c.baseURL, *target.Environment)
+			}
+			if target.APIKey != "" {
+				AddAuthHeaders(legReq, target.APIKey)
+				legSettings.authorize = false
+			}
+		}
+		if err != nil {
+			call.results <- hedgeResult{leg: leg, err: err}
+			return
+		}
+
+		go func() {
+			resp, retries, err := c.requestWithRetry(legCtx, legReq, legSettings)
+			if err == nil {
+				resp, err = awaitFirstByte(resp, settings.readIdle, cancel)
+			}
+			call.results <- hedgeResult{leg: leg, resp: resp, retries: retries, err: err}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+
+	var failed *hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			if call.sent <= len(targets) {
+				launch()
+				timer.Reset(policy.Delay)
+			}
+			continue
+		case r := <-call.results:
+			call.pending--
+			if r.won() {
+				if failed != nil {
+					call.discard(*failed)
+				}
+				c.finishHedge(ctx, req, options, call, r)
+				return r.resp, r.retries, nil
+			}
+
+			if failed == nil {
+				failed = &r
+			} else {
+				call.discard(r)
+			}
+		}
+
+		if call.pending > 0 {
+			continue
+		}
+		// Every request sent so far has failed, so the next duplicate is sent without waiting
+		if call.sent <= len(targets) {
+			launch()
+			continue
+		}
+
+		c.finishHedge(ctx, req, options, call, *failed)
+		return failed.resp, failed.retries, failed.err
+	}
+}
+
+// finishHedge cancels the requests other than the one returned, discarding their responses in the background,
+// and reports the hedged call to the metrics collector
+func (c *HTTPClient) finishHedge(ctx context.Context, req *http.Request, options RequestOptions, call *hedgedCall, r hedgeResult) {
+	for leg, cancel := range call.cancels {
+		if leg != r.leg {
+			cancel()
+		}
+	}
+	if r.resp != nil {
+		r.resp.Body = &releaseOnCloseBody{ReadCloser: r.resp.Body, release: call.cancels[r.leg]}
+	} else {
+		call.cancels[r.leg]()
+	}
+
+	if call.pending > 0 {
+		go func(pending int) {
+			for i := 0; i < pending; i++ {
+				call.discard(<-call.results)
+			}
+		}(call.pending)
+	}
+
+	collector, ok := c.metrics.(HedgeMetricsCollector)
+	if !ok {
+		return
+	}
+	endpoint := options.Operation.Endpoint
+	if endpoint == "" {
+		endpoint = req.URL.Path
+	}
+	m := HedgeMetrics{
+		Endpoint: endpoint,
+		VoiceID:  options.Operation.VoiceID,
+		ModelID:  options.Operation.ModelID,
+		Hedges:   call.sent - 1,
+		Winner:   r.leg,
+		Latency:  time.Since(call.start),
+		Err:      r.err,
+	}
+	if !r.won() {
+		m.Winner = -1
+		if m.Err == nil {
+			m.Err = ctx.Err()
+		}
+	}
+	collector.ObserveHedge(m)
+}
+
+// discard closes the response of a request that lost and releases its context
+func (call *hedgedCall) discard(r hedgeResult) {
+	if r.resp != nil {
+		r.resp.Body.Close()
+	}
+	call.cancels[r.leg]()
+}
+
+// awaitFirstByte waits for the first byte of a successful response body and returns the response with the
+// byte put back. A body that fails before its first byte fails the request. readIdle, if positive, bounds the
+// wait by canceling the request.
+func awaitFirstByte(resp *http.Response, readIdle time.Duration, cancel context.CancelFunc) (*http.Response, error) {
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	var idle atomic.Bool
+	if readIdle > 0 {
+		timer := time.AfterFunc(readIdle, func() {
+			idle.Store(true)
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	buffer := make([]byte, 512)
+	var n int
+	var err error
+	for n == 0 && err == nil {
+		n, err = resp.Body.Read(buffer)
+	}
+	if n == 0 && err != io.EOF {
+		resp.Body.Close()
+		if idle.Load() {
+			return nil, &TimeoutError{Phase: TimeoutPhaseReadIdle, Limit: readIdle}
+		}
+		return nil, err
+	}
+
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buffer[:n]), resp.Body), Closer: resp.Body}
+	return resp, nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hedgeRecorder records the hedged calls reported to it
+type hedgeRecorder struct {
+	mu     sync.Mutex
+	hedges []HedgeMetrics
+}
+
+func (r *hedgeRecorder) ObserveRequest(m RequestMetrics)            {}
+func (r *hedgeRecorder) ObserveWebSocketSession(m WebSocketMetrics) {}
+
+func (r *hedgeRecorder) ObserveHedge(m HedgeMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hedges = append(r.hedges, m)
+}
+
+func (r *hedgeRecorder) last() HedgeMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hedges[len(r.hedges)-1]
+}
+
+func TestHedgedRequestUsesFasterAlternate(t *testing.T) {
+	canceled := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(canceled)
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	var gotPath string
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	metrics := &hedgeRecorder{}
+	client := NewHTTPClient(Config{
+		APIKey:      "test-key",
+		Environment: Environment{BaseURL: slow.URL},
+		Metrics:     metrics,
+	})
+	policy := HedgePolicy{
+		Delay:      10 * time.Millisecond,
+		Alternates: []HedgeTarget{{Environment: &Environment{BaseURL: fast.URL + "/gateway"}}},
+	}
+
+	resp, err := client.Request(context.Background(), http.MethodPost, "v1/text-to-speech/voice", nil, nil, WithHedging(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "fast" || gotPath != "/gateway/v1/text-to-speech/voice" {
+		t.Errorf("expected the alternate to serve the request, got %q from %q", body, gotPath)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("expected the slower request to be canceled")
+	}
+	if m := metrics.last(); m.Hedges != 1 || m.Winner != 1 {
+		t.Errorf("unexpected hedge metrics %+v", m)
+	}
+}
+
+func TestHedgedRequestSkipsRejectedKey(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(APIKeyHeader) != "backup-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}, Config{})
+	policy := HedgePolicy{
+		Delay:      time.Minute,
+		Alternates: []HedgeTarget{{APIKey: "backup-key"}},
+	}
+
+	resp, err := client.Request(context.Background(), http.MethodPost, "v1/text-to-speech/voice", nil, nil, WithHedging(policy), WithMaxRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the duplicate with the backup key to win at once, got %d", resp.StatusCode)
+	}
+}
+
+func TestHedgedRequestReturnsFirstFailure(t *testing.T) {
+	metrics := &hedgeRecorder{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, Config{Metrics: metrics})
+
+	resp, err := client.Request(context.Background(), http.MethodPost, "v1/text-to-speech/voice", nil, nil, WithHedging(HedgePolicy{}), WithMaxRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the failed response, got %d", resp.StatusCode)
+	}
+	if m := metrics.last(); m.Hedges != 1 || m.Winner != -1 {
+		t.Errorf("unexpected hedge metrics %+v", m)
+	}
+}
@@ -1,10 +1,15 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,17 +17,39 @@ import (
 type Environment struct {
 	BaseURL      string
 	WebSocketURL string
+	// Region is the data residency region served by the environment, such as "us" or "eu"
+	Region string
 }
 
 // HTTPClient handles all HTTP communication with the ElevenLabs API
 type HTTPClient struct {
 	httpClient  *http.Client
+	doer        Doer
+	middleware  []Middleware
+	logger      *apiLogger
+	metrics     MetricsCollector
+	tracer      Tracer
 	baseURL     string
 	wsURL       string
-	apiKey      string
+	credentials CredentialProvider
 	userAgent   string
 	timeout     time.Duration
-	retryConfig RetryConfig
+	retryPolicy RetryPolicy
+	limiter     *ConcurrencyLimiter
+	breaker     *CircuitBreaker
+	failover    []Environment
+	residency   *residencyPolicy
+	dialer      *webSocketDialer
+	timeouts    Timeouts
+
+	defaultVoiceID string
+	defaultModelID string
+	ttsHedge       *HedgePolicy
+	ttsCache       Cache
+	ttsCacheTTL    time.Duration
+	catalogs       *CatalogConfig
+
+	wsMiddleware []WebSocketMiddleware
 }
 
 // Config represents HTTP client configuration
@@ -33,24 +60,118 @@ type Config struct {
 	HTTPClient  *http.Client
 	UserAgent   string
 	RetryConfig RetryConfig
+	RetryPolicy RetryPolicy
+	Middleware  []Middleware
+	Logging     LogConfig
+	Metrics     MetricsCollector
+	Tracer      Tracer
+	// ConcurrencyLimiter, if set, bounds in-flight requests and WebSocket sessions
+	ConcurrencyLimiter *ConcurrencyLimiter
+	// CircuitBreaker, if set, fails requests fast after sustained API failures
+	CircuitBreaker *CircuitBreaker
+	// Credentials, if set, supplies the API key for every request instead of APIKey
+	Credentials CredentialProvider
+	// FailoverEnvironments are tried in order when an idempotent request fails in the primary environment
+	FailoverEnvironments []Environment
+	// StrictResidency refuses every request and WebSocket dial outside the region of Environment
+	StrictResidency bool
+	// Timeouts bounds the connection phases of requests and WebSocket sessions. Dial and TLS timeouts apply
+	// to the transport built when HTTPClient is nil; first-byte and read-idle timeouts apply to streams.
+	Timeouts Timeouts
+	// Transport configures proxies, TLS and connection pooling when HTTPClient is nil. WebSocket sessions use the
+	// proxy and TLS settings of the HTTP transport, so they follow HTTPClient's transport if it is set.
+	Transport TransportConfig
+	// DefaultVoiceID and DefaultModelID are used by text-to-speech requests that leave them empty
+	DefaultVoiceID string
+	DefaultModelID string
+	// TTSHedge, if set, hedges text-to-speech Convert and Stream requests
+	TTSHedge *HedgePolicy
+	// TTSCache, if set, serves repeated text-to-speech requests, keeping entries for TTSCacheTTL
+	TTSCache    Cache
+	TTSCacheTTL time.Duration
+	// Catalogs, if set, caches the list of voices for lookups by ID and name
+	Catalogs *CatalogConfig
+	// WebSocketMiddleware wraps the dialer of WebSocket sessions, for example to record or replay them
+	WebSocketMiddleware []WebSocketMiddleware
 }
 
 // NewHTTPClient creates a new HTTP client with the specified configuration
 func NewHTTPClient(config Config) *HTTPClient {
 	if config.HTTPClient == nil {
+		// Timeout bounds each non-streaming attempt through its context instead of http.Client.Timeout,
+		// which would cut off long streams
+		transport := NewTransport(config.Transport)
+		config.Timeouts.applyTo(transport)
 		config.HTTPClient = &http.Client{
-			Timeout: config.Timeout,
+			Transport: transport,
 		}
 	}
 
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = config.RetryConfig
+	}
+
+	tracer := config.Tracer
+	if tracer == nil {
+		tracer = NoopTracer{}
+	}
+
+	dialer := newWebSocketDialer(config.HTTPClient.Transport)
+
+	credentials := config.Credentials
+	if credentials == nil {
+		credentials = NewStaticCredentialProvider(config.APIKey)
+	}
+
+	// Strict residency only fails over within the region and is enforced at the transport,
+	// below middleware and redirects
+	failover := config.FailoverEnvironments
+	var residency *residencyPolicy
+	if config.StrictResidency {
+		region := config.Environment.Region
+		residency = newResidencyPolicy(region, append([]Environment{config.Environment}, failover...))
+
+		failover = nil
+		for _, env := range config.FailoverEnvironments {
+			if region != "" && env.Region == region {
+				failover = append(failover, env)
+			}
+		}
+
+		guarded := *config.HTTPClient
+		guarded.Transport = &residencyTransport{policy: residency, base: config.HTTPClient.Transport}
+		config.HTTPClient = &guarded
+	}
+
 	return &HTTPClient{
 		httpClient:  config.HTTPClient,
+		doer:        Chain(config.HTTPClient, config.Middleware...),
+		middleware:  config.Middleware,
+		logger:      newAPILogger(config.Logging),
+		metrics:     config.Metrics,
+		tracer:      tracer,
 		baseURL:     config.Environment.BaseURL,
-		wsURL:       config.Environment.WebSocketURL,
-		apiKey:      config.APIKey,
+		wsURL:       config.Environment.WebSocketBaseURL(),
+		credentials: credentials,
 		userAgent:   config.UserAgent,
 		timeout:     config.Timeout,
-		retryConfig: config.RetryConfig,
+		retryPolicy: retryPolicy,
+		limiter:     config.ConcurrencyLimiter,
+		breaker:     config.CircuitBreaker,
+		failover:    failover,
+		residency:   residency,
+		dialer:      dialer,
+		timeouts:    config.Timeouts,
+
+		defaultVoiceID: config.DefaultVoiceID,
+		defaultModelID: config.DefaultModelID,
+		ttsHedge:       config.TTSHedge,
+		ttsCache:       config.TTSCache,
+		ttsCacheTTL:    config.TTSCacheTTL,
+		catalogs:       config.Catalogs,
+
+		wsMiddleware: config.WebSocketMiddleware,
 	}
 }
 
@@ -59,83 +180,522 @@ func (c *HTTPClient) GetWebSocketURL() string {
 	return c.wsURL
 }
 
-// GetAPIKey returns the API key
+// NewWebSocketClient creates a WebSocket client that shares this client's credentials and middleware.
+// Only the API key override of the given options is applied.
+func (c *HTTPClient) NewWebSocketClient(opts ...RequestOption) *WebSocketClient {
+	options := NewRequestOptions(opts...)
+
+	ws := NewWebSocketClient(c.wsURL, options.APIKey)
+	ws.credentials = c.credentials
+	ws.residency = c.residency
+	ws.dialer = c.dialer
+	ws.timeouts = c.timeouts
+	ws.middleware = c.middleware
+	ws.wsMiddleware = c.wsMiddleware
+	ws.userAgent = c.userAgent
+	ws.logger = c.logger
+	ws.metrics = c.metrics
+	ws.tracer = c.tracer
+	ws.operation = options.Operation
+	ws.limiter = c.limiter
+	ws.priority = options.PriorityOr(PriorityDefault)
+	return ws
+}
+
+// GetAPIKey returns the API key currently supplied by the credential provider, or "" if there is none
 func (c *HTTPClient) GetAPIKey() string {
-	return c.apiKey
+	apiKey, _ := c.credentials.APIKey(context.Background())
+	return apiKey
+}
+
+// Credentials returns the provider that supplies the API key for every request
+func (c *HTTPClient) Credentials() CredentialProvider {
+	return c.credentials
+}
+
+// CircuitBreaker returns the circuit breaker, or nil if none is configured
+func (c *HTTPClient) CircuitBreaker() *CircuitBreaker {
+	return c.breaker
+}
+
+// DefaultVoiceID returns the voice used by text-to-speech requests without a voice ID, or ""
+func (c *HTTPClient) DefaultVoiceID() string {
+	return c.defaultVoiceID
+}
+
+// DefaultModelID returns the model used by text-to-speech requests without a model ID, or ""
+func (c *HTTPClient) DefaultModelID() string {
+	return c.defaultModelID
+}
+
+// TTSHedgePolicy returns the hedging policy of text-to-speech Convert and Stream requests, or nil
+func (c *HTTPClient) TTSHedgePolicy() *HedgePolicy {
+	return c.ttsHedge
+}
+
+// TTSCache returns the cache of text-to-speech responses and the time entries are kept, or nil
+func (c *HTTPClient) TTSCache() (Cache, time.Duration) {
+	return c.ttsCache, c.ttsCacheTTL
+}
+
+// Catalogs returns the configuration of the cached voice list, or nil if it is not cached
+func (c *HTTPClient) Catalogs() *CatalogConfig {
+	return c.catalogs
+}
+
+// ConcurrencyLimiter returns the limiter shared by requests and WebSocket sessions, or nil if none is configured
+func (c *HTTPClient) ConcurrencyLimiter() *ConcurrencyLimiter {
+	return c.limiter
 }
 
 // Request makes an HTTP request with the specified method, path, body and headers
-func (c *HTTPClient) Request(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	url := c.baseURL + "/" + strings.TrimPrefix(path, "/")
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+func (c *HTTPClient) Request(ctx context.Context, method, path string, body io.Reader, headers map[string]string, opts ...RequestOption) (*http.Response, error) {
+	options := NewRequestOptions(opts...)
+
+	ctx, cancel := withRequestTimeout(ctx, options)
+	req, err := c.newRequest(ctx, method, path, body, headers, options)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	// Add authentication headers
-	AddAuthHeaders(req, c.apiKey)
-
-	// Add user agent
-	req.Header.Set("User-Agent", c.userAgent)
-
-	// Add custom headers
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	ctx, span := c.tracer.StartSpan(ctx, spanName(req.URL.Path, options.Operation), operationAttributes(method, req.URL.Path, options.Operation)...)
+	req = req.WithContext(ctx)
 
 	// Make request with retry logic
-	return c.RequestWithRetry(ctx, req)
+	settings := newRetrySettings(req, options)
+	settings.maxRetries = c.retryPolicy.MaxRetries()
+	if options.MaxRetries != nil {
+		settings.maxRetries = *options.MaxRetries
+	}
+	settings.timeout = c.timeout
+	start := time.Now()
+	resp, retries, err := c.requestHedged(ctx, req, settings, options)
+	return c.finishCall(ctx, span, req, options, start, retries, resp, err, cancel, 0)
 }
 
 // RequestWithRetry executes the HTTP request with retry logic
 func (c *HTTPClient) RequestWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
-	var lastErr error
+	settings := newRetrySettings(req, RequestOptions{})
+	settings.maxRetries = c.retryPolicy.MaxRetries()
+	settings.timeout = c.timeout
+	resp, _, err := c.requestWithRetry(ctx, req, settings)
+	return resp, err
+}
+
+// retrySettings control a single call of requestWithRetry
+type retrySettings struct {
+	maxRetries int
+	priority   Priority
+	// authorize sets the API key from the credential provider before every attempt
+	authorize bool
+	// failover allows failed attempts to be resent to the failover environments
+	failover bool
+	// timeout bounds each attempt including its response body; firstByte bounds the wait for the response headers
+	timeout   time.Duration
+	firstByte time.Duration
+	// readIdle bounds the wait for the first byte of the body of a hedged response
+	readIdle time.Duration
+}
+
+// newRetrySettings returns the settings for a request without retries. Requests that already
+// carry an API key, such as per-request overrides, bypass the credential provider.
+func newRetrySettings(req *http.Request, options RequestOptions) retrySettings {
+	return retrySettings{
+		priority:  options.PriorityOr(PriorityDefault),
+		authorize: req.Header.Get(APIKeyHeader) == "",
+		failover:  isIdempotent(req.Method, options),
+	}
+}
 
-	for attempt := 0; attempt <= c.retryConfig.MaxAttempts; attempt++ {
-		resp, err := c.httpClient.Do(req)
+// requestWithRetry executes the HTTP request, retrying at most maxRetries times as allowed by the retry policy,
+// and returns the number of retries made. The request body is rebuilt from GetBody before every retry.
+// With a concurrency limiter, a 429 response requeues the request on the limiter without using up a retry.
+// A response rejecting the API key, such as 401 or quota_exceeded, is reported to the credential provider
+// and the request is resent at once if the provider has another key.
+func (c *HTTPClient) requestWithRetry(ctx context.Context, req *http.Request, settings retrySettings) (*http.Response, int, error) {
+	var attempts []AttemptError
+	start := time.Now()
+	maxElapsed := c.retryPolicy.MaxElapsed()
+	maxRetries := settings.maxRetries
+	requeues := 0
+	rekeys := 0
+	region := 0
+
+	for attempt, sent := 0, 0; ; sent++ {
+		attemptReq, err := rewindRequest(ctx, req, sent)
+		if err == nil && region > 0 {
+			err = retarget(attemptReq, c.baseURL, c.failover[region-1])
+		}
 		if err != nil {
-			lastErr = err
-			if attempt < c.retryConfig.MaxAttempts {
-				delay := CalculateDelay(attempt, "")
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(delay):
+			attempts = append(attempts, AttemptError{Attempt: sent + 1, Err: err})
+			return nil, attempt, newRetryError(attempts)
+		}
+
+		var apiKey string
+		if settings.authorize {
+			apiKey, err = c.credentials.APIKey(ctx)
+			if err != nil {
+				attempts = append(attempts, AttemptError{Attempt: sent + 1, Err: err})
+				return nil, attempt, newRetryError(attempts)
+			}
+			AddAuthHeaders(attemptReq, apiKey)
+		}
+
+		attemptStart := time.Now()
+		resp, err := c.send(attemptReq, sent, settings)
+		c.logger.logAttempt(ctx, attemptReq, sent, resp, err, time.Since(attemptStart))
+		if err != nil {
+			attempts = append(attempts, AttemptError{Attempt: sent + 1, Err: err})
+		} else {
+			attempts = append(attempts, AttemptError{Attempt: sent + 1, StatusCode: resp.StatusCode})
+		}
+
+		// An unhealthy region is left at once for the next failover environment
+		if settings.failover && region < len(c.failover) && ctx.Err() == nil &&
+			(errors.Is(err, ErrCircuitOpen) || (!errors.Is(err, ErrResidencyViolation) && isCircuitFailure(resp, err))) {
+			region++
+			if resp != nil {
+				resp.Body.Close()
+			}
+			c.logger.logRetry(ctx, attemptReq, sent, 0)
+			continue
+		}
+
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrResidencyViolation) {
+			return nil, attempt, newRetryError(attempts)
+		}
+
+		if settings.authorize && resp != nil {
+			if resp.StatusCode < 400 {
+				recordUsage(c.credentials, apiKey, resp)
+			} else if rekeys < maxCredentialRetries && rejectCredentials(c.credentials, apiKey, resp) {
+				// Fail over to another key if the provider has one
+				if next, err := c.credentials.APIKey(ctx); err == nil && next != apiKey {
+					rekeys++
+					resp.Body.Close()
 					continue
 				}
 			}
-			continue
 		}
 
-		// Check if we should retry based on status code
-		if ShouldRetry(resp.StatusCode) && attempt < c.retryConfig.MaxAttempts {
-			resp.Body.Close()
-			retryAfter := resp.Header.Get("Retry-After")
-			delay := CalculateDelay(attempt, retryAfter)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
-				continue
+		requeue := c.limiter != nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests && requeues < maxConcurrencyRequeues
+		if !requeue && (attempt >= maxRetries || !c.retryPolicy.ShouldRetry(resp, err)) {
+			if err != nil {
+				return nil, attempt, newRetryError(attempts)
+			}
+			resp, err = finalResponse(resp, attempts)
+			return resp, attempt, err
+		}
+
+		delay := c.retryPolicy.Backoff(attempt, resp)
+		if maxElapsed > 0 && time.Since(start)+delay > maxElapsed {
+			if err != nil {
+				return nil, attempt, newRetryError(attempts)
 			}
+			resp, err = finalResponse(resp, attempts)
+			return resp, attempt, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		// Over the concurrency limit: wait for another request to finish, or for the backoff delay if
+		// the slots are held elsewhere, instead of spending a retry
+		var released <-chan struct{}
+		if requeue {
+			requeues++
+			released = c.limiter.Released()
+		} else {
+			attempt++
+		}
+
+		c.logger.logRetry(ctx, attemptReq, sent, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			attempts = append(attempts, AttemptError{Attempt: sent + 2, Err: ctx.Err()})
+			return nil, attempt, newRetryError(attempts)
+		case <-released:
+			timer.Stop()
+		case <-timer.C:
 		}
+	}
+}
+
+// rewindRequest returns the request to send for the given attempt, rebuilding its body for retries
+func rewindRequest(ctx context.Context, req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 {
+		return req, nil
+	}
+
+	attemptReq := req.Clone(ctx)
+	if req.Body == nil || req.Body == http.NoBody {
+		return attemptReq, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("cannot retry request: body is not replayable")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	attemptReq.Body = body
+	return attemptReq, nil
+}
 
+// finalResponse returns the response of the last attempt. An error response after earlier attempts is
+// returned as a RetryError listing every attempt and ending with the API error of the response.
+func finalResponse(resp *http.Response, attempts []AttemptError) (*http.Response, error) {
+	if len(attempts) < 2 || resp.StatusCode < 400 {
 		return resp, nil
 	}
+	err := ParseAPIError(resp)
+	resp.Body.Close()
+	attempts[len(attempts)-1].Err = err
+	return nil, newRetryError(attempts)
+}
 
-	return nil, lastErr
+// newRetryError returns the single attempt error as-is, or a RetryError listing every attempt
+func newRetryError(attempts []AttemptError) error {
+	if len(attempts) == 1 && attempts[0].Err != nil {
+		return attempts[0].Err
+	}
+	return &RetryError{Attempts: attempts}
 }
 
 // Stream makes a streaming HTTP request
-func (c *HTTPClient) Stream(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	url := c.baseURL + "/" + strings.TrimPrefix(path, "/")
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+func (c *HTTPClient) Stream(ctx context.Context, method, path string, body io.Reader, headers map[string]string, opts ...RequestOption) (*http.Response, error) {
+	options := NewRequestOptions(opts...)
+
+	ctx, cancel := withRequestTimeout(ctx, options)
+	req, err := c.newRequest(ctx, method, path, body, headers, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ctx, span := c.tracer.StartSpan(ctx, spanName(req.URL.Path, options.Operation), operationAttributes(method, req.URL.Path, options.Operation)...)
+	req = req.WithContext(ctx)
+
+	// For streaming, we don't want to retry as it could duplicate data. A stream is not bounded as a whole,
+	// only by the wait for its headers and for each read.
+	settings := newRetrySettings(req, options)
+	settings.firstByte = c.timeouts.FirstByte
+	settings.readIdle = c.timeouts.ReadIdle
+	start := time.Now()
+	resp, retries, err := c.requestHedged(ctx, req, settings, options)
+	return c.finishCall(ctx, span, req, options, start, retries, resp, err, cancel, c.timeouts.ReadIdle)
+}
+
+// send sends a single attempt unless the circuit breaker rejects it, holding a concurrency slot
+// if a limiter is configured. The slot is released when the response body is closed.
+func (c *HTTPClient) send(req *http.Request, attempt int, settings retrySettings) (*http.Response, error) {
+	var family string
+	if c.breaker != nil {
+		family = c.breaker.family(req)
+		if len(c.failover) > 0 {
+			// Regions fail independently
+			family += "@" + req.URL.Host
+		}
+		if err := c.breaker.Allow(family); err != nil {
+			return nil, err
+		}
+	}
+
+	release := func() {}
+	if c.limiter != nil {
+		var err error
+		release, err = c.limiter.Acquire(req.Context(), settings.priority)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.doWithTimeouts(req, attempt, settings)
+
+	// Cancellation by the caller says nothing about the health of the API
+	if c.breaker != nil && req.Context().Err() == nil {
+		c.breaker.Record(family, !isCircuitFailure(resp, err))
+	}
+
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if c.limiter != nil {
+		resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: release}
+	}
+	return resp, nil
+}
+
+// doWithTimeouts sends a single attempt bounded by the attempt and first-byte timeouts, reporting
+// timeouts as *TimeoutError. The attempt context is released when the response body is closed.
+func (c *HTTPClient) doWithTimeouts(req *http.Request, attempt int, settings retrySettings) (*http.Response, error) {
+	parent := req.Context()
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if settings.timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, settings.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+
+	var phase connectionPhase
+	req = req.WithContext(phase.withTrace(ctx))
+
+	var timer *time.Timer
+	if settings.firstByte > 0 {
+		timer = time.AfterFunc(settings.firstByte, cancel)
+	}
+
+	resp, err := c.do(req, attempt)
+	// A timer that cannot be stopped has fired or is about to cancel the attempt
+	headersLate := timer != nil && !timer.Stop()
+	if err == nil && headersLate {
+		resp.Body.Close()
+		resp, err = nil, context.Canceled
+	}
+	if err == nil {
+		resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: cancel}
+		return resp, nil
+	}
+	cancel()
+
+	// Cancellation by the caller is not a timeout
+	if parent.Err() != nil {
+		return nil, err
+	}
+	current := phase.get()
+	switch {
+	case headersLate:
+		return nil, &TimeoutError{Phase: TimeoutPhaseFirstByte, Limit: settings.firstByte}
+	case settings.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, &TimeoutError{Phase: TimeoutPhaseRequest, Limit: settings.timeout, Err: context.DeadlineExceeded}
+	case (current == TimeoutPhaseDial || current == TimeoutPhaseTLSHandshake) && c.timeouts.phase(current) > 0 && isTimeout(err):
+		return nil, &TimeoutError{Phase: current, Limit: c.timeouts.phase(current), Err: err}
+	}
+	return nil, err
+}
+
+// do sends a single attempt through the middleware chain inside its own span, propagating the trace context
+func (c *HTTPClient) do(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := c.tracer.StartSpan(req.Context(), "HTTP "+req.Method,
+		Attr("http.request.method", req.Method),
+		Attr("url.full", req.URL.String()),
+		Attr("http.request.resend_count", attempt),
+	)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	if traceParent := span.TraceParent(); traceParent != "" {
+		req.Header.Set(TraceParentHeader, traceParent)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+	if requestID := resp.Header.Get(RequestIDHeader); requestID != "" {
+		span.SetAttributes(Attr("elevenlabs.request_id", requestID))
+	}
+	return resp, nil
+}
+
+// finishCall wraps a successful response body so that metrics and spans are completed once it has been
+// consumed, or completes them immediately for a failed call
+func (c *HTTPClient) finishCall(ctx context.Context, span Span, req *http.Request, options RequestOptions, start time.Time, retries int, resp *http.Response, err error, cancel context.CancelFunc, readIdle time.Duration) (*http.Response, error) {
 	if err != nil {
+		cancel()
+		span.RecordError(err)
+		span.End()
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(requestMetrics(req.Method, req.URL.Path, options.Operation, retries, 0,
+				bodyStats{Duration: time.Since(start), Err: err}))
+		}
 		return nil, err
 	}
 
-	// Add authentication headers
-	AddAuthHeaders(req, c.apiKey)
+	statusCode := resp.StatusCode
+	span.SetAttributes(Attr("http.response.status_code", statusCode), Attr("elevenlabs.retries", retries))
+	if statusCode >= 400 {
+		span.RecordError(fmt.Errorf("HTTP %d %s", statusCode, http.StatusText(statusCode)))
+	}
+
+	// The response body span runs from the first to the last byte received
+	var mu sync.Mutex
+	var bodySpan Span
+	hooks := bodyHooks{
+		onFirstByte: []func(){func() {
+			mu.Lock()
+			defer mu.Unlock()
+			_, bodySpan = c.tracer.StartSpan(ctx, "elevenlabs.response_body")
+		}},
+		onDone: []func(bodyStats){func(stats bodyStats) {
+			mu.Lock()
+			if bodySpan != nil {
+				bodySpan.SetAttributes(Attr("http.response.body.size", stats.Bytes))
+				if stats.Err != nil {
+					bodySpan.RecordError(stats.Err)
+				}
+				bodySpan.End()
+			}
+			mu.Unlock()
+
+			span.SetAttributes(
+				Attr("http.response.body.size", stats.Bytes),
+				Attr("elevenlabs.time_to_first_byte_ms", stats.TimeToFirstByte.Milliseconds()),
+			)
+			if stats.Err != nil {
+				span.RecordError(stats.Err)
+			}
+			span.End()
+		}},
+	}
+
+	if c.metrics != nil {
+		hooks.onDone = append(hooks.onDone, func(stats bodyStats) {
+			c.metrics.ObserveRequest(requestMetrics(req.Method, req.URL.Path, options.Operation, retries, statusCode, stats))
+		})
+	}
+
+	resp.Body = newTrackedBody(resp.Body, start, cancel, readIdle, hooks)
+	return resp, nil
+}
+
+// newRequest builds an HTTP request with authentication, user agent and custom headers applied
+func (c *HTTPClient) newRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string, options RequestOptions) (*http.Request, error) {
+	requestURL, err := c.buildURL(path, options.AdditionalQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buffer bodies that the standard library cannot replay so retries resend the full payload
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+	}
+
+	// Per-request API keys take precedence over the credential provider
+	AddAuthHeaders(req, options.APIKey)
 
 	// Add user agent
 	req.Header.Set("User-Agent", c.userAgent)
@@ -144,7 +704,38 @@ func (c *HTTPClient) Stream(ctx context.Context, method, path string, body io.Re
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	for key, value := range options.AdditionalHeaders {
+		req.Header.Set(key, value)
+	}
 
-	// For streaming, we don't want to retry as it could duplicate data
-	return c.httpClient.Do(req)
+	return req, nil
+}
+
+// buildURL joins the base URL and path and merges any additional query parameters
+func (c *HTTPClient) buildURL(path string, query url.Values) (string, error) {
+	rawURL := c.baseURL + "/" + strings.TrimPrefix(path, "/")
+	if len(query) == 0 {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	values := u.Query()
+	for key, vals := range query {
+		for _, v := range vals {
+			values.Add(key, v)
+		}
+	}
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
+
+// withRequestTimeout derives a context bounded by the per-request timeout, if one is set
+func withRequestTimeout(ctx context.Context, options RequestOptions) (context.Context, context.CancelFunc) {
+	if options.Timeout > 0 {
+		return context.WithTimeout(ctx, options.Timeout)
+	}
+	return context.WithCancel(ctx)
 }
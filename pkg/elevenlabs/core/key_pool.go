@@ -0,0 +1,255 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QuotaProbeInterval is how long a key whose quota state is unknown is left alone: an exhausted key without a
+// known reset time is tried again, and a failed quota fetch is retried, after this interval
+const QuotaProbeInterval = time.Minute
+
+// quotaFetchTimeout bounds a background quota fetch
+const quotaFetchTimeout = 30 * time.Second
+
+// QuotaFetcher loads the character quota of a key from the subscription endpoint
+type QuotaFetcher func(ctx context.Context, apiKey string) (characterCount, characterLimit int64, resetAt time.Time, err error)
+
+// KeyPool spreads requests over several API keys, tracking the remaining character quota of each.
+// Keys rejected as invalid are dropped, and keys whose quota is used up are skipped until their quota resets.
+// It implements CredentialProvider, CredentialInvalidator and QuotaTracker.
+type KeyPool struct {
+	mu   sync.Mutex
+	keys []*pooledKey
+	next int
+
+	fetch QuotaFetcher
+}
+
+// pooledKey is the state of one key in a KeyPool
+type pooledKey struct {
+	apiKey    string
+	limit     int64
+	used      int64
+	known     bool
+	invalid   bool
+	exhausted bool
+	resetAt   time.Time
+
+	// probeAt is when an exhausted key without a known reset time is tried again
+	probeAt time.Time
+	// fetching is set while the quota is being fetched, and fetchAt is when a failed fetch may be retried
+	fetching bool
+	fetchAt  time.Time
+}
+
+// KeyStatus describes the state of one key in a KeyPool
+type KeyStatus struct {
+	APIKey string
+	// CharacterLimit and CharacterCount are the quota and usage of the current billing period,
+	// as last reported by the subscription endpoint and updated from response headers
+	CharacterLimit int64
+	CharacterCount int64
+	// QuotaKnown reports whether the quota has been loaded from the subscription endpoint
+	QuotaKnown bool
+	Invalid    bool
+	Exhausted  bool
+	// ResetAt is when the character quota resets, if known
+	ResetAt time.Time
+}
+
+// Remaining returns the number of characters left in the current billing period
+func (s KeyStatus) Remaining() int64 {
+	if s.CharacterCount >= s.CharacterLimit {
+		return 0
+	}
+	return s.CharacterLimit - s.CharacterCount
+}
+
+// NewKeyPool creates a pool of the given API keys
+func NewKeyPool(apiKeys ...string) *KeyPool {
+	pool := &KeyPool{}
+	for _, apiKey := range apiKeys {
+		if apiKey != "" {
+			pool.keys = append(pool.keys, &pooledKey{apiKey: apiKey})
+		}
+	}
+	return pool
+}
+
+// APIKey implements CredentialProvider, cycling through the keys that are valid and have quota left
+func (p *KeyPool) APIKey(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(p.keys); i++ {
+		key := p.keys[p.next%len(p.keys)]
+		p.next = (p.next + 1) % len(p.keys)
+		if key.usable(now) {
+			// The quota of a key is loaded when it is first used
+			if !key.known {
+				p.fetchQuota(key, now)
+			}
+			return key.apiKey, nil
+		}
+	}
+	return "", ErrNoCredentials
+}
+
+// SetQuotaFetcher sets the function that loads the quota of keys whose quota is unknown, on their first use
+// and when the API reports their quota as used up. Fetches run in the background.
+func (p *KeyPool) SetQuotaFetcher(fetch QuotaFetcher) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fetch = fetch
+}
+
+// Invalidate implements CredentialInvalidator by dropping the key from the pool
+func (p *KeyPool) Invalidate(apiKey string) {
+	p.update(apiKey, func(key *pooledKey) {
+		key.invalid = true
+	})
+}
+
+// QuotaExceeded implements QuotaTracker by skipping the key until its quota resets. If the reset time is not
+// known, the quota is fetched again and the key is tried again after QuotaProbeInterval.
+func (p *KeyPool) QuotaExceeded(apiKey string) {
+	p.update(apiKey, func(key *pooledKey) {
+		now := time.Now()
+		key.exhausted = true
+		if key.resetAt.IsZero() || !now.Before(key.resetAt) {
+			key.resetAt = time.Time{}
+			key.probeAt = now.Add(QuotaProbeInterval)
+			key.fetchAt = time.Time{}
+			p.fetchQuota(key, now)
+		}
+	})
+}
+
+// RecordUsage implements QuotaTracker by charging characters to the key's quota
+func (p *KeyPool) RecordUsage(apiKey string, characters int) {
+	p.update(apiKey, func(key *pooledKey) {
+		key.used += int64(characters)
+		if key.known && key.used >= key.limit && !key.exhausted {
+			key.exhausted = true
+			if key.resetAt.IsZero() {
+				key.probeAt = time.Now().Add(QuotaProbeInterval)
+			}
+		}
+	})
+}
+
+// SetQuota records the quota of a key as reported by the subscription endpoint, making it usable again if
+// it has characters left
+func (p *KeyPool) SetQuota(apiKey string, characterCount, characterLimit int64, resetAt time.Time) {
+	p.update(apiKey, func(key *pooledKey) {
+		key.known = true
+		key.used = characterCount
+		key.limit = characterLimit
+		key.resetAt = resetAt
+		key.exhausted = characterCount >= characterLimit
+		if key.exhausted && resetAt.IsZero() {
+			key.probeAt = time.Now().Add(QuotaProbeInterval)
+		}
+	})
+}
+
+// fetchQuota starts loading the quota of a key in the background, unless a fetch is running or failed
+// recently; the caller must hold the lock
+func (p *KeyPool) fetchQuota(key *pooledKey, now time.Time) {
+	if p.fetch == nil || key.invalid || key.fetching || now.Before(key.fetchAt) {
+		return
+	}
+	key.fetching = true
+
+	fetch, apiKey := p.fetch, key.apiKey
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), quotaFetchTimeout)
+		defer cancel()
+
+		count, limit, resetAt, err := fetch(ctx, apiKey)
+		if err == nil {
+			p.SetQuota(apiKey, count, limit, resetAt)
+		}
+		p.update(apiKey, func(key *pooledKey) {
+			key.fetching = false
+			if err != nil {
+				key.fetchAt = time.Now().Add(QuotaProbeInterval)
+			}
+		})
+	}()
+}
+
+// Keys returns all keys of the pool, including invalid and exhausted ones
+func (p *KeyPool) Keys() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, len(p.keys))
+	for i, key := range p.keys {
+		keys[i] = key.apiKey
+	}
+	return keys
+}
+
+// Status returns the state of every key in the pool
+func (p *KeyPool) Status() []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	status := make([]KeyStatus, len(p.keys))
+	for i, key := range p.keys {
+		status[i] = KeyStatus{
+			APIKey:         key.apiKey,
+			CharacterLimit: key.limit,
+			CharacterCount: key.used,
+			QuotaKnown:     key.known,
+			Invalid:        key.invalid,
+			Exhausted:      key.exhausted && !key.reset(now),
+			ResetAt:        key.resetAt,
+		}
+	}
+	return status
+}
+
+// update applies fn to the state of a key, if it is part of the pool
+func (p *KeyPool) update(apiKey string, fn func(key *pooledKey)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, key := range p.keys {
+		if key.apiKey == apiKey {
+			fn(key)
+			return
+		}
+	}
+}
+
+// usable reports whether the key can be handed out
+func (k *pooledKey) usable(now time.Time) bool {
+	if k.invalid {
+		return false
+	}
+	if k.exhausted && k.reset(now) {
+		// The billing period rolled over; the quota is reloaded on the next use
+		k.exhausted = false
+		k.known = false
+		k.used = 0
+		k.resetAt = time.Time{}
+	}
+	if k.exhausted && k.resetAt.IsZero() && !k.probeAt.IsZero() && !now.Before(k.probeAt) {
+		// The reset time could not be loaded, so the key is tried again and skipped anew if it is still rejected
+		k.exhausted = false
+		k.probeAt = time.Time{}
+	}
+	return !k.exhausted
+}
+
+// reset reports whether the quota reset time of an exhausted key has passed
+func (k *pooledKey) reset(now time.Time) bool {
+	return !k.resetAt.IsZero() && !now.Before(k.resetAt)
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// nextKeys returns the keys the pool hands out for n requests
+func nextKeys(t *testing.T, p *KeyPool, n int) []string {
+	t.Helper()
+	keys := make([]string, n)
+	for i := range keys {
+		key, err := p.APIKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestKeyPoolFetchesQuotaOnFirstUse(t *testing.T) {
+	fetched := make(chan string, 4)
+	p := NewKeyPool("a", "b")
+	p.SetQuotaFetcher(func(ctx context.Context, apiKey string) (int64, int64, time.Time, error) {
+		fetched <- apiKey
+		return 10, 100, time.Time{}, nil
+	})
+
+	select {
+	case key := <-fetched:
+		t.Fatalf("expected no fetch before the first use, got %q", key)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if key := nextKeys(t, p, 1)[0]; key != "a" {
+		t.Fatalf("expected a, got %q", key)
+	}
+	if key := <-fetched; key != "a" {
+		t.Fatalf("expected the quota of a to be fetched, got %q", key)
+	}
+	waitForStatus(t, p, func(status []KeyStatus) bool { return status[0].QuotaKnown })
+
+	status := p.Status()
+	if status[0].Remaining() != 90 || status[1].QuotaKnown {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestKeyPoolSkipsExhaustedKeysUntilReset(t *testing.T) {
+	p := NewKeyPool("a", "b")
+	p.SetQuota("a", 100, 100, time.Now().Add(30*time.Millisecond))
+
+	if keys := nextKeys(t, p, 2); !equalStrings(keys, []string{"b", "b"}) {
+		t.Fatalf("expected only b while a is exhausted, got %v", keys)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if keys := nextKeys(t, p, 2); !equalStrings(keys, []string{"a", "b"}) {
+		t.Fatalf("expected a again after its quota reset, got %v", keys)
+	}
+}
+
+func TestKeyPoolReprobesExhaustedKeyWithoutResetTime(t *testing.T) {
+	p := NewKeyPool("a", "b")
+	p.QuotaExceeded("a")
+	if keys := nextKeys(t, p, 2); !equalStrings(keys, []string{"b", "b"}) {
+		t.Fatalf("expected only b while a is exhausted, got %v", keys)
+	}
+
+	// Once the probe interval has passed, a is tried again
+	p.update("a", func(key *pooledKey) {
+		key.probeAt = time.Now().Add(-time.Second)
+	})
+	if keys := nextKeys(t, p, 2); !equalStrings(keys, []string{"a", "b"}) {
+		t.Fatalf("expected a to be probed, got %v", keys)
+	}
+}
+
+func TestKeyPoolRecordUsageExhaustsKey(t *testing.T) {
+	p := NewKeyPool("a", "b")
+	p.SetQuota("a", 90, 100, time.Now().Add(time.Hour))
+	p.RecordUsage("a", 10)
+
+	if !p.Status()[0].Exhausted {
+		t.Fatal("expected a to be exhausted once its quota is used up")
+	}
+	if keys := nextKeys(t, p, 2); !equalStrings(keys, []string{"b", "b"}) {
+		t.Fatalf("expected only b, got %v", keys)
+	}
+}
+
+func TestKeyPoolDropsInvalidKeys(t *testing.T) {
+	p := NewKeyPool("a", "", "b")
+	if keys := p.Keys(); !equalStrings(keys, []string{"a", "b"}) {
+		t.Fatalf("expected empty keys to be ignored, got %v", keys)
+	}
+
+	p.Invalidate("a")
+	p.Invalidate("b")
+	if _, err := p.APIKey(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+// waitForStatus waits until ready reports true for the status of the pool
+func waitForStatus(t *testing.T, p *KeyPool, ready func([]KeyStatus) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !ready(p.Status()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected status %+v", p.Status())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
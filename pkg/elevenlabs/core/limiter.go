@@ -0,0 +1,224 @@
+package core
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Priority orders requests waiting for a concurrency slot
+type Priority int
+
+const (
+	// PriorityInteractive requests are served before all others
+	PriorityInteractive Priority = iota
+	// PriorityDefault is used when no priority is set
+	PriorityDefault
+	// PriorityBatch requests are served only when no other request is waiting
+	PriorityBatch
+
+	numPriorities = int(PriorityBatch) + 1
+)
+
+// maxConcurrencyRequeues bounds how often a request rejected with 429 is requeued on the limiter
+// before the retry policy takes over
+const maxConcurrencyRequeues = 10
+
+// WithPriority sets the concurrency priority of a single request or WebSocket session
+func WithPriority(priority Priority) RequestOption {
+	return func(o *RequestOptions) {
+		o.Priority = &priority
+	}
+}
+
+// ConcurrencyLimiter bounds the number of in-flight HTTP requests and WebSocket sessions.
+// Waiters are served strictly by priority and first-in, first-out within a priority.
+// A limiter can be shared by several clients using the same subscription.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	queues   [numPriorities][]*limiterWaiter
+	released chan struct{}
+}
+
+// limiterWaiter is a request queued for a slot
+type limiterWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewConcurrencyLimiter creates a limiter allowing limit concurrent requests
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &ConcurrencyLimiter{
+		limit:    limit,
+		released: make(chan struct{}),
+	}
+}
+
+// Acquire waits for a slot and returns a function that releases it.
+// It returns the context error if ctx is done before a slot is available.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, priority Priority) (func(), error) {
+	if priority < 0 || int(priority) >= numPriorities {
+		priority = PriorityDefault
+	}
+
+	l.mu.Lock()
+	if l.inFlight < l.limit && !l.hasWaiters(priority) {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+
+	w := &limiterWaiter{ready: make(chan struct{})}
+	l.queues[priority] = append(l.queues[priority], w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if w.granted {
+			// The slot was handed over concurrently with cancellation; pass it on
+			l.mu.Unlock()
+			l.releaseFunc()()
+			return nil, ctx.Err()
+		}
+		l.remove(priority, w)
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// SetLimit changes the number of allowed concurrent requests, admitting waiters if it grew
+func (l *ConcurrencyLimiter) SetLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.dispatch()
+}
+
+// Limit returns the number of allowed concurrent requests
+func (l *ConcurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// InFlight returns the number of requests currently holding a slot
+func (l *ConcurrencyLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Released returns a channel that is closed the next time any slot is released
+func (l *ConcurrencyLimiter) Released() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.released
+}
+
+// releaseFunc returns an idempotent function releasing one slot
+func (l *ConcurrencyLimiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.inFlight--
+			close(l.released)
+			l.released = make(chan struct{})
+			l.dispatch()
+		})
+	}
+}
+
+// dispatch hands free slots to waiters in priority order; the caller must hold the lock
+func (l *ConcurrencyLimiter) dispatch() {
+	for l.inFlight < l.limit {
+		w := l.pop()
+		if w == nil {
+			return
+		}
+		w.granted = true
+		l.inFlight++
+		close(w.ready)
+	}
+}
+
+// pop removes and returns the next waiter; the caller must hold the lock
+func (l *ConcurrencyLimiter) pop() *limiterWaiter {
+	for p := range l.queues {
+		if len(l.queues[p]) > 0 {
+			w := l.queues[p][0]
+			l.queues[p] = l.queues[p][1:]
+			return w
+		}
+	}
+	return nil
+}
+
+// hasWaiters reports whether any request of equal or higher priority is queued; the caller must hold the lock
+func (l *ConcurrencyLimiter) hasWaiters(priority Priority) bool {
+	for p := 0; p <= int(priority); p++ {
+		if len(l.queues[p]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// remove drops a waiter from its queue; the caller must hold the lock
+func (l *ConcurrencyLimiter) remove(priority Priority, w *limiterWaiter) {
+	queue := l.queues[priority]
+	for i, queued := range queue {
+		if queued == w {
+			l.queues[priority] = append(queue[:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// ConcurrencyLimitForTier returns the documented concurrent request limit for a subscription tier,
+// or 0 if the tier is unknown
+func ConcurrencyLimitForTier(tier string) int {
+	switch strings.ToLower(tier) {
+	case "free":
+		return 2
+	case "starter":
+		return 3
+	case "creator":
+		return 5
+	case "pro":
+		return 10
+	case "scale", "business":
+		return 15
+	default:
+		return 0
+	}
+}
+
+// releaseOnCloseBody releases a concurrency slot when the response body is closed
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the slot
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n requests are queued on the limiter
+func waitQueued(t *testing.T, l *ConcurrencyLimiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		queued := 0
+		for _, queue := range l.queues {
+			queued += len(queue)
+		}
+		l.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued requests", n)
+}
+
+func TestLimiterBoundsInFlight(t *testing.T) {
+	l := NewConcurrencyLimiter(2)
+	release1, _ := l.Acquire(context.Background(), PriorityDefault)
+	release2, _ := l.Acquire(context.Background(), PriorityDefault)
+	defer release2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, PriorityDefault); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the third request to wait, got %v", err)
+	}
+	if got := l.InFlight(); got != 2 {
+		t.Errorf("expected 2 requests in flight, got %d", got)
+	}
+
+	// Releasing twice frees a single slot
+	release1()
+	release1()
+	release3, err := l.Acquire(context.Background(), PriorityDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release3()
+	if got := l.InFlight(); got != 1 {
+		t.Errorf("expected 1 request in flight, got %d", got)
+	}
+}
+
+func TestLimiterServesByPriority(t *testing.T) {
+	l := NewConcurrencyLimiter(1)
+	release, _ := l.Acquire(context.Background(), PriorityDefault)
+
+	order := make(chan Priority, 3)
+	enqueue := func(priority Priority) {
+		go func() {
+			next, err := l.Acquire(context.Background(), priority)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- priority
+			next()
+		}()
+	}
+	enqueue(PriorityBatch)
+	waitQueued(t, l, 1)
+	enqueue(PriorityDefault)
+	waitQueued(t, l, 2)
+	enqueue(PriorityInteractive)
+	waitQueued(t, l, 3)
+
+	release()
+	for _, want := range []Priority{PriorityInteractive, PriorityDefault, PriorityBatch} {
+		if got := <-order; got != want {
+			t.Fatalf("expected priority %d to be served, got %d", want, got)
+		}
+	}
+}
+
+func TestLimiterCanceledWaiterGivesUpItsPlace(t *testing.T) {
+	l := NewConcurrencyLimiter(1)
+	release, _ := l.Acquire(context.Background(), PriorityDefault)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := l.Acquire(ctx, PriorityDefault)
+		done <- err
+	}()
+	waitQueued(t, l, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the waiter to be canceled, got %v", err)
+	}
+
+	release()
+	if got := l.InFlight(); got != 0 {
+		t.Errorf("expected no requests in flight, got %d", got)
+	}
+}
+
+func TestLimiterSetLimitAdmitsWaiters(t *testing.T) {
+	l := NewConcurrencyLimiter(1)
+	release, _ := l.Acquire(context.Background(), PriorityDefault)
+	defer release()
+
+	admitted := make(chan func())
+	go func() {
+		next, _ := l.Acquire(context.Background(), PriorityDefault)
+		admitted <- next
+	}()
+	waitQueued(t, l, 1)
+
+	l.SetLimit(2)
+	select {
+	case next := <-admitted:
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to be admitted when the limit grew")
+	}
+}
+
+func TestRequestHoldsSlotUntilBodyIsClosed(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}, Config{ConcurrencyLimiter: NewConcurrencyLimiter(1)})
+
+	first, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Request(ctx, http.MethodGet, "v1/test", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the second request to wait for the slot, got %v", err)
+	}
+
+	first.Body.Close()
+	second, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Body.Close()
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the response header carrying the ElevenLabs request ID
+const RequestIDHeader = "request-id"
+
+// redacted replaces sensitive values in log output
+const redacted = "[REDACTED]"
+
+// LogVerbosity controls how much detail is logged for each request
+type LogVerbosity int
+
+const (
+	// LogVerbosityBasic logs method, path, status, latency, retry attempts and request ID
+	LogVerbosityBasic LogVerbosity = iota
+	// LogVerbosityHeaders additionally logs request and response headers
+	LogVerbosityHeaders
+	// LogVerbosityBodies additionally logs JSON request bodies and WebSocket messages
+	LogVerbosityBodies
+)
+
+// LogConfig configures structured logging of API traffic
+type LogConfig struct {
+	// Logger receives the log records; logging is disabled when nil
+	Logger *slog.Logger
+	// Verbosity controls how much detail is logged
+	Verbosity LogVerbosity
+	// RedactHeaders lists additional header names whose values are redacted
+	RedactHeaders []string
+	// LogText disables redaction of text fields in logged request bodies
+	LogText bool
+}
+
+// defaultRedactedHeaders are always redacted from logged headers
+var defaultRedactedHeaders = []string{APIKeyHeader, "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedBodyFields are JSON fields holding user text that are redacted from logged bodies
+var redactedBodyFields = map[string]bool{
+	"text":          true,
+	"previous_text": true,
+	"next_text":     true,
+}
+
+// summarizedBodyFields are JSON fields holding audio that are logged by size only
+var summarizedBodyFields = map[string]bool{
+	"audio":         true,
+	"audio_base_64": true,
+}
+
+// apiLogger writes structured log records for HTTP requests and WebSocket sessions.
+// A nil *apiLogger discards everything.
+type apiLogger struct {
+	config        LogConfig
+	redactHeaders map[string]bool
+}
+
+// newAPILogger creates a logger from the configuration, returning nil when logging is disabled
+func newAPILogger(config LogConfig) *apiLogger {
+	if config.Logger == nil {
+		return nil
+	}
+
+	redact := make(map[string]bool)
+	for _, name := range defaultRedactedHeaders {
+		redact[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range config.RedactHeaders {
+		redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	return &apiLogger{
+		config:        config,
+		redactHeaders: redact,
+	}
+}
+
+// logAttempt logs the outcome of a single HTTP request attempt
+func (l *apiLogger) logAttempt(ctx context.Context, req *http.Request, attempt int, resp *http.Response, err error, latency time.Duration) {
+	if l == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt+1),
+		slog.Duration("latency", latency),
+	}
+
+	if l.config.Verbosity >= LogVerbosityHeaders {
+		attrs = append(attrs, slog.Any("request_headers", l.headers(req.Header)))
+	}
+	if l.config.Verbosity >= LogVerbosityBodies && req.GetBody != nil {
+		if body, ok := l.requestBody(req); ok {
+			attrs = append(attrs, slog.String("request_body", body))
+		}
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		l.config.Logger.LogAttrs(ctx, slog.LevelWarn, "elevenlabs request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if requestID := resp.Header.Get(RequestIDHeader); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if l.config.Verbosity >= LogVerbosityHeaders {
+		attrs = append(attrs, slog.Any("response_headers", l.headers(resp.Header)))
+	}
+
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	l.config.Logger.LogAttrs(ctx, level, "elevenlabs request", attrs...)
+}
+
+// logRetry logs that a request attempt is about to be retried
+func (l *apiLogger) logRetry(ctx context.Context, req *http.Request, attempt int, delay time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.config.Logger.LogAttrs(ctx, slog.LevelInfo, "elevenlabs retrying request",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt+1),
+		slog.Duration("delay", delay),
+	)
+}
+
+// logWebSocket logs a WebSocket lifecycle event
+func (l *apiLogger) logWebSocket(ctx context.Context, level slog.Level, event, path string, attrs ...slog.Attr) {
+	if l == nil {
+		return
+	}
+
+	attrs = append([]slog.Attr{slog.String("event", event), slog.String("path", path)}, attrs...)
+	l.config.Logger.LogAttrs(ctx, level, "elevenlabs websocket", attrs...)
+}
+
+// logWebSocketMessage logs a WebSocket message when body logging is enabled
+func (l *apiLogger) logWebSocketMessage(ctx context.Context, direction, path string, data []byte) {
+	if l == nil || l.config.Verbosity < LogVerbosityBodies {
+		return
+	}
+
+	l.config.Logger.LogAttrs(ctx, slog.LevelDebug, "elevenlabs websocket message",
+		slog.String("direction", direction),
+		slog.String("path", path),
+		slog.String("message", l.redactBody(data)),
+	)
+}
+
+// headers returns a copy of the headers with sensitive values redacted
+func (l *apiLogger) headers(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if l.redactHeaders[http.CanonicalHeaderKey(name)] {
+			result[name] = redacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// requestBody reads a copy of the request body for logging
+func (l *apiLogger) requestBody(req *http.Request) (string, bool) {
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil || len(data) == 0 {
+		return "", false
+	}
+	return l.redactBody(data), true
+}
+
+// redactBody redacts user text from a JSON body; non-JSON bodies are summarized by size
+func (l *apiLogger) redactBody(data []byte) string {
+	if l.config.LogText {
+		return string(data)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(data))
+	}
+
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(data))
+	}
+	return string(out)
+}
+
+// redactValue replaces text fields anywhere in a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if s, ok := field.(string); ok && summarizedBodyFields[key] {
+				v[key] = fmt.Sprintf("[%d bytes]", len(s))
+				continue
+			}
+			if s, ok := field.(string); ok && redactedBodyFields[key] {
+				v[key] = fmt.Sprintf("%s (%d chars)", redacted, len([]rune(s)))
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import "time"
+
+// Operation labels a request with the API operation it performs, for metrics and tracing
+type Operation struct {
+	// Endpoint is a low-cardinality name for the operation, such as "text_to_speech.convert"
+	Endpoint string
+	VoiceID  string
+	ModelID  string
+	// Characters is the number of characters submitted for synthesis
+	Characters int
+}
+
+// WithOperation labels a single request with the API operation it performs
+func WithOperation(op Operation) RequestOption {
+	return func(o *RequestOptions) {
+		o.Operation = op
+	}
+}
+
+// RequestMetrics describes a single completed API call, including all of its retries
+type RequestMetrics struct {
+	Endpoint            string
+	Method              string
+	VoiceID             string
+	ModelID             string
+	StatusCode          int
+	Retries             int
+	TimeToFirstByte     time.Duration
+	Duration            time.Duration
+	BytesReceived       int64
+	CharactersSubmitted int
+	Err                 error
+}
+
+// WebSocketMetrics describes a single finished WebSocket session
+type WebSocketMetrics struct {
+	Endpoint            string
+	VoiceID             string
+	ModelID             string
+	Duration            time.Duration
+	MessagesSent        int64
+	MessagesReceived    int64
+	BytesReceived       int64
+	AudioSeconds        float64
+	CharactersSubmitted int64
+	Err                 error
+}
+
+// MetricsCollector receives one event per API call and per WebSocket session.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	ObserveRequest(m RequestMetrics)
+	ObserveWebSocketSession(m WebSocketMetrics)
+}
+
+// requestMetrics builds the metrics event for a call from its request, response and body statistics
+func requestMetrics(method, path string, op Operation, retries int, statusCode int, stats bodyStats) RequestMetrics {
+	endpoint := op.Endpoint
+	if endpoint == "" {
+		endpoint = path
+	}
+
+	return RequestMetrics{
+		Endpoint:            endpoint,
+		Method:              method,
+		VoiceID:             op.VoiceID,
+		ModelID:             op.ModelID,
+		StatusCode:          statusCode,
+		Retries:             retries,
+		TimeToFirstByte:     stats.TimeToFirstByte,
+		Duration:            stats.Duration,
+		BytesReceived:       stats.Bytes,
+		CharactersSubmitted: op.Characters,
+		Err:                 stats.Err,
+	}
+}
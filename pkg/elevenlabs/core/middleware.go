@@ -0,0 +1,29 @@
+package core
+
+import "net/http"
+
+// Doer executes a single HTTP request
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer to observe or modify every outgoing request and its response
+type Middleware func(next Doer) Doer
+
+// Chain wraps doer with the given middleware so that the first middleware sees each request first
+func Chain(doer Doer, middleware ...Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			doer = middleware[i](doer)
+		}
+	}
+	return doer
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ErrNoMorePages is returned by Pager.Next after the last page
+var ErrNoMorePages = errors.New("elevenlabs: no more pages")
+
+// Page is one page of a cursor-paginated list
+type Page[T any] struct {
+	Items []T
+	// HasMore reports whether another page follows
+	HasMore bool
+	// NextCursor selects the following page; the API calls it next_page_token, last_sort_id or cursor
+	NextCursor string
+}
+
+// PageFetcher fetches the page following cursor, or the first page if cursor is empty,
+// with at most pageSize items if pageSize is positive
+type PageFetcher[T any] func(ctx context.Context, cursor string, pageSize int) (*Page[T], error)
+
+// Pager iterates over the pages of a list endpoint, passing the cursor of each page to the request
+// for the next one. A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	pageSize int
+	cursor   string
+	started  bool
+	done     bool
+}
+
+// NewPager creates a pager that fetches pages of at most pageSize items; zero uses the endpoint's default
+func NewPager[T any](fetch PageFetcher[T], pageSize int) *Pager[T] {
+	return &Pager[T]{
+		fetch:    fetch,
+		pageSize: pageSize,
+	}
+}
+
+// SetPageSize changes the size of the pages fetched from now on
+func (p *Pager[T]) SetPageSize(pageSize int) {
+	p.pageSize = pageSize
+}
+
+// HasNext reports whether Next may return another page
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next fetches the next page of items, or returns ErrNoMorePages after the last page.
+// A failed fetch can be retried by calling Next again.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	page, err := p.fetch(ctx, p.cursor, p.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// A page without a new cursor ends the list even if it claims more, so a misbehaving
+	// endpoint cannot make the pager loop over the same page
+	if !page.HasMore || page.NextCursor == "" || (p.started && page.NextCursor == p.cursor) {
+		p.done = true
+	}
+	p.started = true
+	p.cursor = page.NextCursor
+	return page.Items, nil
+}
+
+// All fetches the remaining pages and returns their items
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	err := p.ForEach(ctx, func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// ForEach calls fn for each item of the remaining pages, fetching pages as needed. It stops at the first
+// error returned by fn or by a fetch, or when ctx is canceled, and returns that error.
+func (p *Pager[T]) ForEach(ctx context.Context, fn func(item T) error) error {
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PageItem is an item sent by Pager.Channel, or the error that ended the iteration
+type PageItem[T any] struct {
+	Item  T
+	Error error
+}
+
+// Channel sends the items of the remaining pages on the returned channel, which is closed after the last
+// item or after an error is sent. Canceling ctx stops fetching and closes the channel.
+func (p *Pager[T]) Channel(ctx context.Context) <-chan PageItem[T] {
+	ch := make(chan PageItem[T])
+
+	go func() {
+		defer close(ch)
+
+		err := p.ForEach(ctx, func(item T) error {
+			select {
+			case ch <- PageItem[T]{Item: item}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			select {
+			case ch <- PageItem[T]{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return ch
+}
+
+// ListEndpoint describes the parameters and fields a cursor-paginated endpoint uses
+type ListEndpoint struct {
+	Method string
+	Path   string
+	// ItemsField is the JSON field of the response holding the items of a page; responses without it fail
+	ItemsField string
+	// CursorParam is the query parameter selecting the page after a cursor, "cursor" if empty
+	CursorParam string
+	// CursorField is the JSON field of the response holding the next cursor. If empty,
+	// next_page_token, next_cursor, last_sort_id and cursor are tried in order.
+	CursorField string
+	// PageSizeParam is the query parameter bounding the page size, "page_size" if empty
+	PageSizeParam string
+}
+
+// defaultCursorFields are the response fields holding the next cursor across list endpoints
+var defaultCursorFields = []string{"next_page_token", "next_cursor", "last_sort_id", "cursor"}
+
+// NewListPager creates a pager over any cursor-paginated endpoint, decoding the items of each page as T.
+// Requests are sent with the client's authentication, retries and middleware, and with opts.
+func NewListPager[T any](c *HTTPClient, endpoint ListEndpoint, pageSize int, opts ...RequestOption) *Pager[T] {
+	return NewPager(func(ctx context.Context, cursor string, pageSize int) (*Page[T], error) {
+		reqOpts := append([]RequestOption{}, opts...)
+		if cursor != "" {
+			param := endpoint.CursorParam
+			if param == "" {
+				param = "cursor"
+			}
+			reqOpts = append(reqOpts, WithQueryParam(param, cursor))
+		}
+		if pageSize > 0 {
+			param := endpoint.PageSizeParam
+			if param == "" {
+				param = "page_size"
+			}
+			reqOpts = append(reqOpts, WithQueryParam(param, strconv.Itoa(pageSize)))
+		}
+
+		method := endpoint.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		var fields map[string]json.RawMessage
+		if _, err := c.Do(ctx, method, endpoint.Path, nil, &fields, reqOpts...); err != nil {
+			return nil, err
+		}
+		return decodePage[T](fields, endpoint)
+	}, pageSize)
+}
+
+// decodePage extracts the items and pagination fields of a list response
+func decodePage[T any](fields map[string]json.RawMessage, endpoint ListEndpoint) (*Page[T], error) {
+	// A missing items field is a misconfigured endpoint, not an empty page
+	if endpoint.ItemsField == "" {
+		return nil, fmt.Errorf("list endpoint %s has no items field", endpoint.Path)
+	}
+	raw, ok := fields[endpoint.ItemsField]
+	if !ok {
+		return nil, fmt.Errorf("response of %s has no %s field", endpoint.Path, endpoint.ItemsField)
+	}
+	page := &Page[T]{}
+	if err := json.Unmarshal(raw, &page.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", endpoint.ItemsField, err)
+	}
+
+	cursorFields := defaultCursorFields
+	if endpoint.CursorField != "" {
+		cursorFields = []string{endpoint.CursorField}
+	}
+	for _, field := range cursorFields {
+		if raw, ok := fields[field]; ok {
+			var cursor interface{}
+			if err := json.Unmarshal(raw, &cursor); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", field, err)
+			}
+			if cursor != nil {
+				// Numeric cursors such as sort IDs are passed back as they were sent
+				page.NextCursor = string(raw)
+				if s, ok := cursor.(string); ok {
+					page.NextCursor = s
+				}
+			}
+			if page.NextCursor != "" {
+				break
+			}
+		}
+	}
+
+	// Endpoints without has_more end when they stop returning a cursor
+	page.HasMore = page.NextCursor != ""
+	if raw, ok := fields["has_more"]; ok {
+		if err := json.Unmarshal(raw, &page.HasMore); err != nil {
+			return nil, fmt.Errorf("failed to decode has_more: %w", err)
+		}
+	}
+	return page, nil
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagesFetcher serves the given pages in order, using the index of the next page as its cursor
+func pagesFetcher(pages [][]int) PageFetcher[int] {
+	return func(ctx context.Context, cursor string, pageSize int) (*Page[int], error) {
+		index := 0
+		if cursor != "" {
+			index, _ = strconv.Atoi(cursor)
+		}
+		page := &Page[int]{Items: pages[index]}
+		if index+1 < len(pages) {
+			page.HasMore = true
+			page.NextCursor = strconv.Itoa(index + 1)
+		}
+		return page, nil
+	}
+}
+
+func TestPagerAll(t *testing.T) {
+	p := NewPager(pagesFetcher([][]int{{1, 2}, {3}, {4, 5}}), 0)
+	items, err := p.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(items) != "[1 2 3 4 5]" {
+		t.Errorf("unexpected items %v", items)
+	}
+	if p.HasNext() {
+		t.Error("expected no more pages")
+	}
+	if _, err := p.Next(context.Background()); !errors.Is(err, ErrNoMorePages) {
+		t.Errorf("expected ErrNoMorePages, got %v", err)
+	}
+}
+
+func TestPagerStopsOnRepeatedCursor(t *testing.T) {
+	var calls int
+	p := NewPager(func(ctx context.Context, cursor string, pageSize int) (*Page[int], error) {
+		calls++
+		return &Page[int]{Items: []int{calls}, HasMore: true, NextCursor: "same"}, nil
+	}, 0)
+
+	items, err := p.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || len(items) != 2 {
+		t.Errorf("expected the pager to stop once the cursor repeats, got %d calls and %v", calls, items)
+	}
+}
+
+func TestPagerRetriesFailedFetch(t *testing.T) {
+	fetch := pagesFetcher([][]int{{1}, {2}})
+	failed := false
+	p := NewPager(func(ctx context.Context, cursor string, pageSize int) (*Page[int], error) {
+		if cursor == "1" && !failed {
+			failed = true
+			return nil, errors.New("temporary")
+		}
+		return fetch(ctx, cursor, pageSize)
+	}, 0)
+
+	if _, err := p.All(context.Background()); err == nil {
+		t.Fatal("expected the failed fetch to be returned")
+	}
+	items, err := p.All(context.Background())
+	if err != nil || fmt.Sprint(items) != "[2]" {
+		t.Errorf("expected the failed page to be fetched again, got %v, %v", items, err)
+	}
+}
+
+func TestPagerChannelSendsError(t *testing.T) {
+	fetchErr := errors.New("boom")
+	fetch := pagesFetcher([][]int{{1, 2}, {3}})
+	p := NewPager(func(ctx context.Context, cursor string, pageSize int) (*Page[int], error) {
+		if cursor != "" {
+			return nil, fetchErr
+		}
+		return fetch(ctx, cursor, pageSize)
+	}, 0)
+
+	var items []int
+	var err error
+	for item := range p.Channel(context.Background()) {
+		if item.Error != nil {
+			err = item.Error
+			continue
+		}
+		items = append(items, item.Item)
+	}
+	if fmt.Sprint(items) != "[1 2]" || !errors.Is(err, fetchErr) {
+		t.Errorf("expected the first page then the error, got %v, %v", items, err)
+	}
+}
+
+func TestListPagerPassesCursorAndPageSize(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("start_after") == "" {
+			w.Write([]byte(`{"history":[{"id":"a"},{"id":"b"}],"last_sort_id":7,"has_more":true}`))
+			return
+		}
+		w.Write([]byte(`{"history":[{"id":"c"}],"last_sort_id":null,"has_more":false}`))
+	}, Config{})
+
+	type item struct {
+		ID string `json:"id"`
+	}
+	p := NewListPager[item](client, ListEndpoint{
+		Path:          "v1/history",
+		ItemsField:    "history",
+		CursorParam:   "start_after",
+		PageSizeParam: "page_size",
+	}, 2)
+
+	items, err := p.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[2].ID != "c" {
+		t.Errorf("unexpected items %+v", items)
+	}
+	if len(queries) != 2 || queries[0] != "page_size=2" || queries[1] != "page_size=2&start_after=7" {
+		t.Errorf("unexpected queries %v", queries)
+	}
+}
+
+func TestListPagerFailsWithoutItemsField(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"voices":[]}`))
+	}, Config{})
+
+	p := NewListPager[map[string]any](client, ListEndpoint{Path: "v1/history", ItemsField: "history"}, 0)
+	if _, err := p.Next(context.Background()); err == nil {
+		t.Fatal("expected a response without the items field to fail")
+	}
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodeQuery encodes the fields of a struct tagged with `url:"name"` as query parameters.
+//
+// Nil pointers are skipped, and zero values are skipped if the tag has the omitempty option.
+// Slices and arrays add one parameter per element. Strings, booleans, numbers and their named types,
+// such as enums, are formatted as-is; time.Time is formatted as RFC 3339, or as Unix seconds with the
+// unix option; other types must implement encoding.TextMarshaler. Embedded structs are flattened.
+func EncodeQuery(v interface{}) (url.Values, error) {
+	values := make(url.Values)
+	if v == nil {
+		return values, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query: cannot encode %s, expected a struct", rv.Type())
+	}
+
+	if err := encodeStruct(values, rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ExpandPath replaces each {name} in template with the path-escaped value of the field of v
+// tagged with `path:"name"`. Every placeholder must have a non-empty value.
+func ExpandPath(template string, v interface{}) (string, error) {
+	params := make(map[string]string)
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		if err := collectPathParams(params, rv); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("path: unterminated placeholder in %q", template)
+		}
+		name := template[start+1 : start+end]
+		value := params[name]
+		if value == "" {
+			return "", fmt.Errorf("path: missing value for {%s}", name)
+		}
+
+		b.WriteString(template[:start])
+		b.WriteString(url.PathEscape(value))
+		template = template[start+end+1:]
+	}
+}
+
+// RequestTarget expands the path template with the path fields of req and returns an option adding
+// its query fields to the request
+func RequestTarget(template string, req interface{}) (string, RequestOption, error) {
+	path, err := ExpandPath(template, req)
+	if err != nil {
+		return "", nil, err
+	}
+	query, err := EncodeQuery(req)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, WithQuery(query), nil
+}
+
+// WithQuery adds query parameters, such as the result of EncodeQuery, to a single request
+func WithQuery(values url.Values) RequestOption {
+	return func(o *RequestOptions) {
+		if o.AdditionalQuery == nil {
+			o.AdditionalQuery = make(url.Values)
+		}
+		for key, vals := range values {
+			for _, value := range vals {
+				o.AdditionalQuery.Add(key, value)
+			}
+		}
+	}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeStruct adds the tagged fields of a struct to values
+func encodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		tag, tagged := field.Tag.Lookup("url")
+		if !tagged {
+			if field.Anonymous {
+				for fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						break
+					}
+					fv = fv.Elem()
+				}
+				if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+					if err := encodeStruct(values, fv); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		name, opts := parseTag(tag)
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if err := encodeField(values, name, fv, opts); err != nil {
+			return fmt.Errorf("query: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// encodeField adds the value of one field, skipping nil pointers and empty values marked omitempty
+func encodeField(values url.Values, name string, fv reflect.Value, opts tagOptions) error {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if opts.has("omitempty") && fv.IsZero() {
+		return nil
+	}
+
+	if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && fv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < fv.Len(); i++ {
+			elem := fv.Index(i)
+			for elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Interface {
+				if elem.IsNil() {
+					break
+				}
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Interface {
+				continue
+			}
+			s, err := formatValue(elem, opts)
+			if err != nil {
+				return err
+			}
+			values.Add(name, s)
+		}
+		return nil
+	}
+
+	s, err := formatValue(fv, opts)
+	if err != nil {
+		return err
+	}
+	values.Add(name, s)
+	return nil
+}
+
+// formatValue formats a single non-pointer value
+func formatValue(v reflect.Value, opts tagOptions) (string, error) {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if opts.has("unix") {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// collectPathParams records the values of the fields of a struct tagged with `path:"name"`
+func collectPathParams(params map[string]string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, opts := parseTag(field.Tag.Get("path"))
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Pointer {
+			continue
+		}
+
+		s, err := formatValue(fv, opts)
+		if err != nil {
+			return fmt.Errorf("path: field %s: %w", field.Name, err)
+		}
+		params[name] = s
+	}
+	return nil
+}
+
+// tagOptions are the comma-separated options following the name in a struct tag
+type tagOptions []string
+
+// parseTag splits a struct tag into its name and options
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
+
+// has reports whether the option is set
+func (o tagOptions) has(option string) bool {
+	for _, opt := range o {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
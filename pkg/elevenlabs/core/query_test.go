@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryLevel string
+
+type queryUpper string
+
+func (u queryUpper) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(u))), nil
+}
+
+type queryPage struct {
+	Cursor *string `url:"cursor"`
+}
+
+type queryParams struct {
+	queryPage
+	Name     string     `url:"name"`
+	Empty    string     `url:"empty,omitempty"`
+	Zero     int        `url:"zero"`
+	Enabled  *bool      `url:"enabled"`
+	Level    queryLevel `url:"level"`
+	Tags     []string   `url:"tag"`
+	Ratio    float64    `url:"ratio"`
+	Since    time.Time  `url:"since"`
+	Until    time.Time  `url:"until,unix"`
+	Upper    queryUpper `url:"upper"`
+	Skipped  string     `url:"-"`
+	Body     string     `json:"body"`
+	internal string
+}
+
+func TestEncodeQuery(t *testing.T) {
+	enabled := false
+	cursor := "next"
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	values, err := EncodeQuery(&queryParams{
+		queryPage: queryPage{Cursor: &cursor},
+		Name:      "a b",
+		Enabled:   &enabled,
+		Level:     "high",
+		Tags:      []string{"x", "y"},
+		Ratio:     0.5,
+		Since:     since,
+		Until:     since,
+		Upper:     "up",
+		Skipped:   "skip",
+		Body:      "body",
+		internal:  "internal",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"cursor":  {"next"},
+		"name":    {"a b"},
+		"zero":    {"0"},
+		"enabled": {"false"},
+		"level":   {"high"},
+		"tag":     {"x", "y"},
+		"ratio":   {"0.5"},
+		"since":   {"2024-01-02T03:04:05Z"},
+		"until":   {"1704164645"},
+		"upper":   {"UP"},
+	}
+	if got, want := values.Encode(), want.Encode(); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestEncodeQuerySkipsNilPointers(t *testing.T) {
+	values, err := EncodeQuery(queryParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Has("cursor") || values.Has("enabled") {
+		t.Errorf("expected nil pointers to be skipped, got %s", values.Encode())
+	}
+
+	if values, err := EncodeQuery((*queryParams)(nil)); err != nil || len(values) != 0 {
+		t.Errorf("expected no values for a nil struct, got %v, %v", values, err)
+	}
+	if _, err := EncodeQuery("not a struct"); err == nil {
+		t.Error("expected an error for a non-struct value")
+	}
+	if _, err := EncodeQuery(struct {
+		Bad map[string]string `url:"bad"`
+	}{Bad: map[string]string{}}); err == nil {
+		t.Error("expected an error for an unsupported field type")
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	target := struct {
+		VoiceID string `path:"voice_id"`
+		Index   int    `path:"index"`
+	}{VoiceID: "a/b c", Index: 3}
+
+	got, err := ExpandPath("v1/voices/{voice_id}/samples/{index}", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "v1/voices/a%2Fb%20c/samples/3"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if _, err := ExpandPath("v1/voices/{voice_id}", struct{}{}); err == nil {
+		t.Error("expected an error for a missing path value")
+	}
+	if _, err := ExpandPath("v1/voices/{voice_id", target); err == nil {
+		t.Error("expected an error for an unterminated placeholder")
+	}
+}
+
+func TestRequestTargetSendsPathAndQuery(t *testing.T) {
+	var gotPath, gotQuery string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.EscapedPath(), r.URL.RawQuery
+	}, Config{})
+
+	req := struct {
+		VoiceID string `path:"voice_id" json:"-"`
+		Format  string `url:"output_format"`
+	}{VoiceID: "a/b", Format: "mp3_44100_128"}
+	path, query, err := RequestTarget("v1/text-to-speech/{voice_id}", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Request(context.Background(), http.MethodPost, path, nil, nil, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if gotPath != "/v1/text-to-speech/a%2Fb" || gotQuery != "output_format=mp3_44100_128" {
+		t.Errorf("unexpected request target %s?%s", gotPath, gotQuery)
+	}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Regions served by the ElevenLabs API
+const (
+	RegionGlobal = "global"
+	RegionUS     = "us"
+	RegionEU     = "eu"
+)
+
+// ErrResidencyViolation is matched by errors.Is when a request was refused by strict data residency
+var ErrResidencyViolation = errors.New("elevenlabs: request outside the data residency region")
+
+// ResidencyError is returned instead of sending a request or dialing a WebSocket outside the configured region
+type ResidencyError struct {
+	Region string
+	URL    string
+}
+
+// Error implements the error interface
+func (e *ResidencyError) Error() string {
+	if e.Region == "" {
+		return fmt.Sprintf("data residency: refusing %s because no region is configured", e.URL)
+	}
+	return fmt.Sprintf("data residency: refusing %s outside region %q", e.URL, e.Region)
+}
+
+// Is reports whether target is ErrResidencyViolation
+func (e *ResidencyError) Is(target error) bool {
+	return target == ErrResidencyViolation
+}
+
+// WebSocketBaseURL returns the WebSocket URL of the environment, deriving it from the base URL if it is not set
+func (e Environment) WebSocketBaseURL() string {
+	if e.WebSocketURL != "" {
+		return e.WebSocketURL
+	}
+	switch {
+	case strings.HasPrefix(e.BaseURL, "https://"):
+		return "wss://" + strings.TrimPrefix(e.BaseURL, "https://")
+	case strings.HasPrefix(e.BaseURL, "http://"):
+		return "ws://" + strings.TrimPrefix(e.BaseURL, "http://")
+	default:
+		return e.BaseURL
+	}
+}
+
+// residencyPolicy allows only the hosts of the environments in one region
+type residencyPolicy struct {
+	region string
+	hosts  map[string]bool
+}
+
+// newResidencyPolicy allows the HTTP and WebSocket hosts of the environments in the given region
+func newResidencyPolicy(region string, environments []Environment) *residencyPolicy {
+	p := &residencyPolicy{region: region, hosts: make(map[string]bool)}
+	for _, env := range environments {
+		if region == "" || env.Region != region {
+			continue
+		}
+		for _, rawURL := range []string{env.BaseURL, env.WebSocketBaseURL()} {
+			if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+				p.hosts[strings.ToLower(u.Host)] = true
+			}
+		}
+	}
+	return p
+}
+
+// check returns a *ResidencyError if u is outside the region
+func (p *residencyPolicy) check(u *url.URL) error {
+	if p == nil || p.hosts[strings.ToLower(u.Host)] {
+		return nil
+	}
+	return &ResidencyError{Region: p.region, URL: u.Scheme + "://" + u.Host + u.Path}
+}
+
+// residencyTransport refuses requests outside the region at the transport, so that neither middleware nor
+// redirects can send data elsewhere
+type residencyTransport struct {
+	policy *residencyPolicy
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *residencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.policy.check(req.URL); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// isIdempotent reports whether a request can safely be sent to another region
+func isIdempotent(method string, options RequestOptions) bool {
+	if options.Idempotent != nil {
+		return *options.Idempotent
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithIdempotent marks a request as safe or unsafe to resend to a failover environment,
+// overriding the default based on its method
+func WithIdempotent(idempotent bool) RequestOption {
+	return func(o *RequestOptions) {
+		o.Idempotent = &idempotent
+	}
+}
+
+// retarget points a request built for the base URL from at the base URL of another environment. The path below
+// from is kept, so base URLs with a path prefix, such as a gateway in front of the API, are honored.
+func retarget(req *http.Request, from string, env Environment) error {
+	base, err := url.Parse(env.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid failover base URL: %w", err)
+	}
+	primary, err := url.Parse(from)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	req.URL.Scheme = base.Scheme
+	req.URL.Host = base.Host
+	req.URL.Path = strings.TrimSuffix(base.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(primary.Path, "/"))
+	if req.URL.RawPath != "" {
+		req.URL.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + strings.TrimPrefix(req.URL.RawPath, strings.TrimSuffix(primary.EscapedPath(), "/"))
+	}
+	req.Host = ""
+	return nil
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRetargetKeepsBasePathPrefix(t *testing.T) {
+	tests := []struct {
+		from, to, url, want string
+	}{
+		{"https://api.elevenlabs.io", "https://api.eu.elevenlabs.io", "https://api.elevenlabs.io/v1/voices?a=1", "https://api.eu.elevenlabs.io/v1/voices?a=1"},
+		{"https://api.elevenlabs.io", "https://gw.example.com/elevenlabs/", "https://api.elevenlabs.io/v1/voices", "https://gw.example.com/elevenlabs/v1/voices"},
+		{"https://proxy.example.com/primary", "https://api.elevenlabs.io", "https://proxy.example.com/primary/v1/voices", "https://api.elevenlabs.io/v1/voices"},
+		{"https://a.example.com/x", "https://b.example.com/y", "https://a.example.com/x/v1/voices/a%2Fb", "https://b.example.com/y/v1/voices/a%2Fb"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		if err := retarget(req, tt.from, Environment{BaseURL: tt.to}); err != nil {
+			t.Fatal(err)
+		}
+		if got := req.URL.String(); got != tt.want {
+			t.Errorf("retarget(%q, %q) = %q, want %q", tt.url, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRequestFailsOverToEnvironmentWithPathPrefix(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	var gotPath string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte("ok"))
+	}))
+	defer gateway.Close()
+
+	client := NewHTTPClient(Config{
+		APIKey:               "test-key",
+		Environment:          Environment{BaseURL: primary.URL},
+		FailoverEnvironments: []Environment{{BaseURL: gateway.URL + "/elevenlabs"}},
+	})
+	resp, err := client.Request(context.Background(), http.MethodGet, "v1/voices", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || gotPath != "/elevenlabs/v1/voices" {
+		t.Errorf("expected the gateway to serve /elevenlabs/v1/voices, got %d for %q", resp.StatusCode, gotPath)
+	}
+}
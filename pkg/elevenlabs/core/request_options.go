@@ -0,0 +1,99 @@
+package core
+
+import (
+	"net/url"
+	"time"
+)
+
+// RequestOptions holds per-request overrides applied on top of the client configuration
+type RequestOptions struct {
+	Timeout           time.Duration
+	MaxRetries        *int
+	APIKey            string
+	AdditionalHeaders map[string]string
+	AdditionalQuery   url.Values
+	ChunkSize         int
+	Operation         Operation
+	Priority          *Priority
+	Idempotent        *bool
+	Hedge             *HedgePolicy
+	SkipCache         bool
+}
+
+// RequestOption configures a single API call
+type RequestOption func(*RequestOptions)
+
+// NewRequestOptions applies the given options and returns the result
+func NewRequestOptions(opts ...RequestOption) RequestOptions {
+	var options RequestOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+// WithRequestTimeout sets the timeout for a single request, including reading the response body
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithMaxRetries overrides the maximum number of retries for a single request
+func WithMaxRetries(maxRetries int) RequestOption {
+	return func(o *RequestOptions) {
+		o.MaxRetries = &maxRetries
+	}
+}
+
+// WithAPIKey overrides the API key for a single request
+func WithAPIKey(apiKey string) RequestOption {
+	return func(o *RequestOptions) {
+		o.APIKey = apiKey
+	}
+}
+
+// WithHeader adds an extra header to a single request
+func WithHeader(key, value string) RequestOption {
+	return func(o *RequestOptions) {
+		if o.AdditionalHeaders == nil {
+			o.AdditionalHeaders = make(map[string]string)
+		}
+		o.AdditionalHeaders[key] = value
+	}
+}
+
+// WithQueryParam adds an extra query parameter to a single request
+func WithQueryParam(key, value string) RequestOption {
+	return func(o *RequestOptions) {
+		if o.AdditionalQuery == nil {
+			o.AdditionalQuery = make(url.Values)
+		}
+		o.AdditionalQuery.Add(key, value)
+	}
+}
+
+// WithChunkSize sets the chunk size used when streaming a response body
+func WithChunkSize(size int) RequestOption {
+	return func(o *RequestOptions) {
+		o.ChunkSize = size
+	}
+}
+
+// ChunkSizeOr returns the configured chunk size or the given default
+func (o RequestOptions) ChunkSizeOr(defaultSize int) int {
+	if o.ChunkSize > 0 {
+		return o.ChunkSize
+	}
+	return defaultSize
+}
+
+// PriorityOr returns the configured concurrency priority or the given default
+func (o RequestOptions) PriorityOr(defaultPriority Priority) Priority {
+	if o.Priority != nil {
+		return *o.Priority
+	}
+	return defaultPriority
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response headers returned by the ElevenLabs API
+const (
+	HistoryItemIDHeader = "history-item-id"
+	CharacterCostHeader = "character-cost"
+)
+
+// ResponseMetadata holds the status and headers of an API response
+type ResponseMetadata struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID identifies the request; pass it in PreviousRequestIDs to stitch generations together
+	RequestID string
+	// HistoryItemID identifies the generated audio in the speech history, if it was recorded
+	HistoryItemID string
+	// CharacterCost is the number of characters billed for the request, or 0 if the header is absent
+	CharacterCost int
+	ContentType   string
+	// Cached reports whether the response was replayed from the cache instead of the API
+	Cached bool
+}
+
+// NewResponseMetadata extracts the metadata of a response
+func NewResponseMetadata(resp *http.Response) ResponseMetadata {
+	characterCost, _ := strconv.Atoi(strings.TrimSpace(resp.Header.Get(CharacterCostHeader)))
+
+	return ResponseMetadata{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		RequestID:     resp.Header.Get(RequestIDHeader),
+		HistoryItemID: resp.Header.Get(HistoryItemIDHeader),
+		CharacterCost: characterCost,
+		ContentType:   resp.Header.Get("Content-Type"),
+	}
+}
+
+// RawResponse is the result of an API call together with the metadata of its response
+type RawResponse[T any] struct {
+	ResponseMetadata
+	Data T
+
+	// streamErr reports the error that ended a streamed Data early
+	streamErr func() error
+}
+
+// Err returns the error that ended a streamed response before the end of its body, such as a read-idle timeout
+// or a reset connection. It is set once the channel in Data is closed, and is nil for complete responses.
+func (r *RawResponse[T]) Err() error {
+	if r.streamErr == nil {
+		return nil
+	}
+	return r.streamErr()
+}
+
+// WithStreamErr sets the function Err reports the error of a streamed response with
+func (r *RawResponse[T]) WithStreamErr(err func() error) *RawResponse[T] {
+	r.streamErr = err
+	return r
+}
+
+// NewRawResponse pairs data with the metadata of the response it was read from
+func NewRawResponse[T any](resp *http.Response, data T) *RawResponse[T] {
+	return &RawResponse[T]{
+		ResponseMetadata: NewResponseMetadata(resp),
+		Data:             data,
+	}
+}
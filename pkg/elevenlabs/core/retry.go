@@ -1,19 +1,49 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// RetryConfig configures retry behavior for HTTP requests
+// RetryPolicy decides whether a failed request attempt is retried and how long to wait before the next one
+type RetryPolicy interface {
+	// MaxRetries returns the maximum number of retries after the first attempt
+	MaxRetries() int
+	// MaxElapsed bounds the total time spent on a request including retries; zero means no limit
+	MaxElapsed() time.Duration
+	// ShouldRetry reports whether an attempt that produced resp or err should be retried
+	ShouldRetry(resp *http.Response, err error) bool
+	// Backoff returns the delay before the retry following the given zero-based attempt
+	Backoff(attempt int, resp *http.Response) time.Duration
+}
+
+// BackoffFunc computes the delay before the retry following the given zero-based attempt
+type BackoffFunc func(attempt int) time.Duration
+
+// RetryConfig configures retry behavior for HTTP requests and is the default RetryPolicy
 type RetryConfig struct {
 	MaxAttempts       int
 	InitialDelay      time.Duration
 	MaxDelay          time.Duration
 	BackoffMultiplier float64
 	JitterFactor      float64
+
+	// MaxElapsedTime stops retrying once the request has been running this long; zero means no limit
+	MaxElapsedTime time.Duration
+	// RetryableStatusCodes overrides the status codes that are retried; nil uses ShouldRetry
+	RetryableStatusCodes []int
+	// RetryableError decides whether a transport error is retried; nil retries every error
+	// except context cancellation
+	RetryableError func(err error) bool
+	// BackoffFunc replaces the exponential backoff calculation when set
+	BackoffFunc BackoffFunc
 }
 
 // DefaultRetryConfig returns a sensible default retry configuration
@@ -27,6 +57,89 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
+// MaxRetries returns the maximum number of retries after the first attempt
+func (c RetryConfig) MaxRetries() int {
+	return c.MaxAttempts
+}
+
+// MaxElapsed returns the maximum total time spent on a request including retries
+func (c RetryConfig) MaxElapsed() time.Duration {
+	return c.MaxElapsedTime
+}
+
+// ShouldRetry reports whether an attempt that produced resp or err should be retried
+func (c RetryConfig) ShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if c.RetryableError != nil {
+			return c.RetryableError(err)
+		}
+		return true
+	}
+
+	if resp == nil {
+		return false
+	}
+
+	if c.RetryableStatusCodes != nil {
+		for _, code := range c.RetryableStatusCodes {
+			if resp.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	return ShouldRetry(resp.StatusCode)
+}
+
+// Backoff returns the delay before the retry following the given attempt, honoring Retry-After
+func (c RetryConfig) Backoff(attempt int, resp *http.Response) time.Duration {
+	retryAfter := ""
+	if resp != nil {
+		retryAfter = resp.Header.Get("Retry-After")
+	}
+	return c.CalculateDelay(attempt, retryAfter)
+}
+
+// CalculateDelay calculates the delay before retrying a request using this configuration
+func (c RetryConfig) CalculateDelay(attempt int, retryAfter string) time.Duration {
+	maxDelay := c.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+
+	// If server provides Retry-After header, respect it (with reasonable limits)
+	if delay, ok := ParseRetryAfter(retryAfter, time.Now()); ok && delay <= maxDelay {
+		return delay
+	}
+
+	if c.BackoffFunc != nil {
+		return c.BackoffFunc(attempt)
+	}
+
+	// Calculate exponential backoff
+	backoffDelay := float64(c.InitialDelay) * math.Pow(c.BackoffMultiplier, float64(attempt))
+
+	// Cap at maximum delay
+	if backoffDelay > float64(maxDelay) {
+		backoffDelay = float64(maxDelay)
+	}
+
+	// Add jitter to avoid thundering herd
+	jitter := backoffDelay * c.JitterFactor * (rand.Float64()*2 - 1) // Random between -jitterFactor and +jitterFactor
+	finalDelay := backoffDelay + jitter
+
+	// Ensure delay is non-negative
+	if finalDelay < 0 {
+		finalDelay = 0
+	}
+
+	return time.Duration(finalDelay)
+}
+
 // ShouldRetry determines if a request should be retried based on the status code
 func ShouldRetry(statusCode int) bool {
 	retryable400s := []int{429, 408, 409}
@@ -46,37 +159,73 @@ func ShouldRetry(statusCode int) bool {
 	return false
 }
 
-// CalculateDelay calculates the delay before retrying a request
+// CalculateDelay calculates the delay before retrying a request using the default retry configuration
 func CalculateDelay(attempt int, retryAfter string) time.Duration {
-	// If server provides Retry-After header, respect it (with reasonable limits)
-	if retryAfter != "" {
-		if seconds, err := strconv.Atoi(retryAfter); err == nil {
-			delay := time.Duration(seconds) * time.Second
-			if delay <= 30*time.Second { // Cap at 30 seconds
-				return delay
-			}
+	return DefaultRetryConfig().CalculateDelay(attempt, retryAfter)
+}
+
+// ParseRetryAfter parses a Retry-After header given either as delay-seconds or as an HTTP-date
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
 		}
+		return time.Duration(seconds) * time.Second, true
 	}
 
-	// Use exponential backoff with jitter
-	config := DefaultRetryConfig()
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
 
-	// Calculate exponential backoff
-	backoffDelay := float64(config.InitialDelay) * math.Pow(config.BackoffMultiplier, float64(attempt))
+	return 0, false
+}
 
-	// Cap at maximum delay
-	if backoffDelay > float64(config.MaxDelay) {
-		backoffDelay = float64(config.MaxDelay)
+// AttemptError describes the outcome of a single failed request attempt
+type AttemptError struct {
+	Attempt    int
+	StatusCode int
+	// Err is the transport error of the attempt, or the API error of the response that ended the request
+	Err error
+}
+
+// Error implements the error interface
+func (e AttemptError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("attempt %d: %v", e.Attempt, e.Err)
 	}
+	return fmt.Sprintf("attempt %d: HTTP %d %s", e.Attempt, e.StatusCode, http.StatusText(e.StatusCode))
+}
 
-	// Add jitter to avoid thundering herd
-	jitter := backoffDelay * config.JitterFactor * (rand.Float64()*2 - 1) // Random between -jitterFactor and +jitterFactor
-	finalDelay := backoffDelay + jitter
+// RetryError is returned when a request could not be completed, listing every attempt
+type RetryError struct {
+	Attempts []AttemptError
+}
 
-	// Ensure delay is non-negative
-	if finalDelay < 0 {
-		finalDelay = 0
+// Error implements the error interface
+func (e *RetryError) Error() string {
+	parts := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		parts[i] = attempt.Error()
 	}
+	return fmt.Sprintf("request failed after %d attempts: %s", len(e.Attempts), strings.Join(parts, "; "))
+}
 
-	return time.Duration(finalDelay)
+// Unwrap returns the underlying error of every attempt
+func (e *RetryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts))
+	for _, attempt := range e.Attempts {
+		if attempt.Err != nil {
+			errs = append(errs, attempt.Err)
+		}
+	}
+	return errs
 }
@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient creates a client for a test server that retries quickly
+func newTestClient(t *testing.T, handler http.HandlerFunc, config Config) *HTTPClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config.APIKey = "test-key"
+	config.Environment = Environment{BaseURL: server.URL}
+	if config.RetryPolicy == nil && config.RetryConfig.MaxAttempts == 0 {
+		config.RetryConfig = RetryConfig{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 1}
+	}
+	return NewHTTPClient(config)
+}
+
+func TestRequestRetriesWithRewoundBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		n := len(bodies)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}, Config{})
+
+	// A reader without GetBody is buffered so every attempt sends the whole payload
+	resp, err := client.Request(context.Background(), http.MethodPost, "v1/test", io.MultiReader(strings.NewReader("payload")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != "payload" {
+			t.Errorf("attempt %d sent %q", i+1, body)
+		}
+	}
+}
+
+func TestRequestDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}, Config{})
+
+	resp, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRequestHonorsMaxRetriesOption(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, Config{})
+
+	resp, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil, WithMaxRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRequestListsEveryFailedAttempt(t *testing.T) {
+	client := NewHTTPClient(Config{
+		APIKey:      "test-key",
+		Environment: Environment{BaseURL: "http://api.invalid"},
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})},
+		RetryConfig: RetryConfig{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 1},
+	})
+
+	_, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a RetryError, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d: %v", len(retryErr.Attempts), err)
+	}
+}
+
+func TestRequestListsEveryAttemptBeforeAnErrorResponse(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":{"status":"voice_not_found","message":"Voice not found"}}`))
+	}, Config{})
+
+	_, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected a RetryError, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 || retryErr.Attempts[0].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 3 attempts starting with a 503, got %v", err)
+	}
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected the final API error to be wrapped, got %v", err)
+	}
+}
+
+func TestRequestWrapsFinalErrorWhenRetriesRunOut(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, Config{})
+
+	_, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	var retryErr *RetryError
+	var serverErr *ServerError
+	if !errors.As(err, &retryErr) || !errors.As(err, &serverErr) {
+		t.Fatalf("expected a RetryError wrapping a ServerError, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(retryErr.Attempts))
+	}
+}
+
+func TestRequestStopsAtMaxElapsedTime(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, Config{RetryConfig: RetryConfig{MaxAttempts: 5, InitialDelay: time.Second, BackoffMultiplier: 1, MaxElapsedTime: 100 * time.Millisecond}})
+
+	start := time.Now()
+	resp, err := client.Request(context.Background(), http.MethodGet, "v1/test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the request to give up before the backoff, took %s", elapsed)
+	}
+}
+
+func TestRewindRequestWithoutGetBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "http://api.invalid", io.NopCloser(strings.NewReader("payload")))
+	req.GetBody = nil
+
+	if _, err := rewindRequest(context.Background(), req, 0); err != nil {
+		t.Errorf("expected the first attempt to use the request as-is, got %v", err)
+	}
+	if _, err := rewindRequest(context.Background(), req, 1); err == nil {
+		t.Error("expected an error for a body that cannot be replayed")
+	}
+}
+
+func TestCalculateDelay(t *testing.T) {
+	config := RetryConfig{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, BackoffMultiplier: 2}
+
+	tests := []struct {
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{0, "", 100 * time.Millisecond},
+		{2, "", 400 * time.Millisecond},
+		{10, "", time.Second},
+		{0, "1", time.Second},
+		// Retry-After beyond the maximum delay falls back to the backoff
+		{0, "60", 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := config.CalculateDelay(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("CalculateDelay(%d, %q) = %s, want %s", tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"3", 3 * time.Second, true},
+		{" 0 ", 0, true},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
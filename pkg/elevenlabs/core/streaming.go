@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sync"
 )
 
 // StreamChunk represents a chunk of streaming data
@@ -15,7 +16,15 @@ type StreamChunk struct {
 
 // StreamResponse streams an HTTP response body in chunks
 func StreamResponse(resp *http.Response, chunkSize int) <-chan []byte {
+	ch, _ := StreamResponseWithError(resp, chunkSize)
+	return ch
+}
+
+// StreamResponseWithError streams an HTTP response body in chunks like StreamResponse. The returned function
+// reports the error that ended the body early, once the channel is closed.
+func StreamResponseWithError(resp *http.Response, chunkSize int) (<-chan []byte, func() error) {
 	ch := make(chan []byte)
+	result := &StreamResult{}
 
 	go func() {
 		defer close(ch)
@@ -32,15 +41,34 @@ func StreamResponse(resp *http.Response, chunkSize int) <-chan []byte {
 			}
 			if err != nil {
 				if err != io.EOF {
-					// For non-EOF errors, we could send them through a separate error channel
-					// For now, we'll just break out of the loop
+					result.Set(err)
 				}
 				break
 			}
 		}
 	}()
 
-	return ch
+	return ch, result.Err
+}
+
+// StreamResult holds the error that ended a stream, set by the goroutine producing it
+type StreamResult struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Set records the error that ended the stream
+func (r *StreamResult) Set(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Err returns the error that ended the stream, or nil
+func (r *StreamResult) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
 }
 
 // StreamWithContext streams an HTTP response with context support
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TimeoutPhase names the part of a request that timed out
+type TimeoutPhase string
+
+// Phases reported by TimeoutError
+const (
+	TimeoutPhaseDial         TimeoutPhase = "dial"
+	TimeoutPhaseTLSHandshake TimeoutPhase = "tls_handshake"
+	TimeoutPhaseFirstByte    TimeoutPhase = "first_byte"
+	TimeoutPhaseReadIdle     TimeoutPhase = "read_idle"
+	// TimeoutPhaseRequest is the overall timeout of a non-streaming request attempt
+	TimeoutPhaseRequest TimeoutPhase = "request"
+)
+
+// Timeouts bounds the phases of a connection separately, so that long audio streams stay open as long as
+// bytes keep flowing while stalled connections fail fast. Zero disables a timeout.
+type Timeouts struct {
+	// Dial bounds opening the TCP connection, including the proxy connection
+	Dial time.Duration
+	// TLSHandshake bounds the TLS handshake
+	TLSHandshake time.Duration
+	// FirstByte bounds the wait for the response headers of a stream or the WebSocket handshake response
+	FirstByte time.Duration
+	// ReadIdle bounds the wait for the next bytes of a streamed response or the next WebSocket message
+	ReadIdle time.Duration
+}
+
+// DefaultTimeouts returns the default connection timeouts
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Dial:         10 * time.Second,
+		TLSHandshake: 10 * time.Second,
+		FirstByte:    30 * time.Second,
+		ReadIdle:     30 * time.Second,
+	}
+}
+
+// TimeoutError is returned when a phase of a request or WebSocket session exceeds its timeout
+type TimeoutError struct {
+	Phase TimeoutPhase
+	// Limit is the timeout that was exceeded
+	Limit time.Duration
+	// Err is the underlying error, if any
+	Err error
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("elevenlabs: %s timeout after %s", e.Phase, e.Limit)
+}
+
+// Unwrap returns the underlying error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout implements net.Error
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// Temporary implements net.Error
+func (e *TimeoutError) Temporary() bool {
+	return true
+}
+
+// applyTo sets the dial and TLS handshake timeouts of an HTTP transport
+func (t Timeouts) applyTo(transport *http.Transport) {
+	if t.Dial > 0 {
+		dialer := &net.Dialer{Timeout: t.Dial, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if t.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshake
+	}
+}
+
+// phase returns the timeout of a phase
+func (t Timeouts) phase(phase TimeoutPhase) time.Duration {
+	switch phase {
+	case TimeoutPhaseDial:
+		return t.Dial
+	case TimeoutPhaseTLSHandshake:
+		return t.TLSHandshake
+	case TimeoutPhaseFirstByte:
+		return t.FirstByte
+	case TimeoutPhaseReadIdle:
+		return t.ReadIdle
+	default:
+		return 0
+	}
+}
+
+// connectionPhase tracks which connection phase an attempt is in, to name the phase of a timeout
+type connectionPhase struct {
+	mu    sync.Mutex
+	phase TimeoutPhase
+}
+
+// set records the current phase
+func (p *connectionPhase) set(phase TimeoutPhase) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phase = phase
+}
+
+// get returns the current phase, or "" between phases
+func (p *connectionPhase) get() TimeoutPhase {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.phase
+}
+
+// withTrace returns a context that records the connection phases of a request
+func (p *connectionPhase) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		ConnectStart: func(string, string) { p.set(TimeoutPhaseDial) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				p.set("")
+			}
+		},
+		TLSHandshakeStart: func() { p.set(TimeoutPhaseTLSHandshake) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				p.set("")
+			}
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { p.set(TimeoutPhaseFirstByte) },
+		GotFirstResponseByte: func() { p.set("") },
+	})
+}
+
+// isTimeout reports whether err is a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
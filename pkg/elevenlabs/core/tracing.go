@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+)
+
+// TraceParentHeader is the W3C Trace Context header used to propagate spans
+const TraceParentHeader = "traceparent"
+
+// Attribute is a key/value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr creates a span attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer starts spans around API calls, response streams and WebSocket sessions.
+// Implementations adapt it to a tracing system such as OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span as a child of any span in ctx and returns a context carrying it
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// End completes the span
+	End()
+	// SetAttributes attaches attributes to the span
+	SetAttributes(attrs ...Attribute)
+	// RecordError records an error on the span and marks it as failed
+	RecordError(err error)
+	// TraceParent returns the W3C traceparent header value identifying this span, or "" to skip propagation
+	TraceParent() string
+}
+
+// NoopTracer is a Tracer that records nothing
+type NoopTracer struct{}
+
+// StartSpan returns ctx unchanged and a span that does nothing
+func (NoopTracer) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is a Span that does nothing
+type noopSpan struct{}
+
+func (noopSpan) End()                             {}
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) TraceParent() string              { return "" }
+
+// FormatTraceParent builds a W3C traceparent header value for adapters implementing Span.TraceParent
+func FormatTraceParent(traceID [16]byte, spanID [8]byte, sampled bool) string {
+	flags := "00"
+	if sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(traceID[:]) + "-" + hex.EncodeToString(spanID[:]) + "-" + flags
+}
+
+// operationAttributes returns the span attributes describing an operation
+func operationAttributes(method, path string, op Operation) []Attribute {
+	attrs := []Attribute{
+		Attr("http.request.method", method),
+		Attr("url.path", path),
+	}
+	if op.Endpoint != "" {
+		attrs = append(attrs, Attr("elevenlabs.endpoint", op.Endpoint))
+	}
+	if op.VoiceID != "" {
+		attrs = append(attrs, Attr("elevenlabs.voice_id", op.VoiceID))
+	}
+	if op.ModelID != "" {
+		attrs = append(attrs, Attr("elevenlabs.model_id", op.ModelID))
+	}
+	if op.Characters > 0 {
+		attrs = append(attrs, Attr("elevenlabs.characters", op.Characters))
+	}
+	return attrs
+}
+
+// spanName returns the name of the span covering a whole API call
+func spanName(path string, op Operation) string {
+	if op.Endpoint != "" {
+		return "elevenlabs." + op.Endpoint
+	}
+	return "elevenlabs " + path
+}
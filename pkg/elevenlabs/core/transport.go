@@ -0,0 +1,284 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TransportConfig configures the connections of both HTTP requests and WebSocket sessions
+type TransportConfig struct {
+	// Proxy routes connections through an HTTP or HTTPS proxy; user info in the URL is sent as
+	// Proxy-Authorization. Nil uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY from the environment.
+	Proxy *url.URL
+	// RootCAs replaces the system certificate pool used to verify servers and HTTPS proxies
+	RootCAs *x509.CertPool
+	// Certificates are presented to servers that request a client certificate (mutual TLS)
+	Certificates []tls.Certificate
+	// MaxIdleConnsPerHost bounds the idle connections kept per host; zero keeps the net/http default
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes idle connections after this long; zero keeps the net/http default
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 restricts HTTP requests to HTTP/1.1
+	DisableHTTP2 bool
+}
+
+// NewTransport creates an HTTP transport from the configuration, starting from the settings of
+// http.DefaultTransport
+func NewTransport(config TransportConfig) *http.Transport {
+	var transport *http.Transport
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	} else {
+		transport = &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			ForceAttemptHTTP2: true,
+		}
+	}
+
+	if config.Proxy != nil {
+		transport.Proxy = http.ProxyURL(config.Proxy)
+	}
+	if config.RootCAs != nil || len(config.Certificates) > 0 {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:      config.RootCAs,
+			Certificates: config.Certificates,
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < config.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = config.MaxIdleConnsPerHost
+		}
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	if config.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto disables HTTP/2
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return transport
+}
+
+// webSocketDialer dials WebSocket connections with the proxy and TLS settings of an HTTP transport,
+// so realtime sessions take the same route as HTTP requests
+type webSocketDialer struct {
+	proxy     func(*http.Request) (*url.URL, error)
+	tlsConfig *tls.Config
+}
+
+// newWebSocketDialer copies the proxy and TLS settings of rt if it is an *http.Transport,
+// and otherwise uses the proxy from the environment
+func newWebSocketDialer(rt http.RoundTripper) *webSocketDialer {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	d := &webSocketDialer{proxy: http.ProxyFromEnvironment}
+	if transport, ok := rt.(*http.Transport); ok {
+		d.proxy = transport.Proxy
+		if transport.TLSClientConfig != nil {
+			d.tlsConfig = transport.TLSClientConfig.Clone()
+		}
+	}
+	return d
+}
+
+// dialer returns a websocket.Dialer for a handshake with u, bounding each phase of the handshake by timeouts
+func (d *webSocketDialer) dialer(u *url.URL, timeouts Timeouts) (*websocket.Dialer, error) {
+	// The handshake as a whole keeps its former bound unless its phases are bounded separately
+	dialer := &websocket.Dialer{HandshakeTimeout: DefaultRetryConfig().MaxDelay}
+	if timeouts.FirstByte > 0 {
+		dialer.HandshakeTimeout = 0
+	}
+
+	var proxyURL *url.URL
+	if d.proxy != nil {
+		// Proxies are chosen by the HTTP scheme matching the WebSocket scheme, as for HTTP requests
+		target := *u
+		switch target.Scheme {
+		case "ws":
+			target.Scheme = "http"
+		case "wss":
+			target.Scheme = "https"
+		}
+		var err error
+		proxyURL, err = d.proxy(&http.Request{URL: &target, Header: http.Header{}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve proxy: %w", err)
+		}
+	}
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if proxyURL == nil {
+			return dialTimeout(ctx, network, addr, timeouts.Dial)
+		}
+		return d.tunnel(ctx, network, addr, proxyURL, timeouts)
+	}
+	dialer.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return withFirstByteDeadline(conn, timeouts.FirstByte), nil
+	}
+	dialer.NetDialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		tlsConn, err := handshakeTLS(ctx, conn, d.clientTLSConfig(host), timeouts.TLSHandshake)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return withFirstByteDeadline(tlsConn, timeouts.FirstByte), nil
+	}
+	return dialer, nil
+}
+
+// firstByteConn bounds the WebSocket handshake exchange once the connection is established. The dialer may
+// set an earlier deadline, and clearing the deadline after the handshake lifts the bound.
+type firstByteConn struct {
+	net.Conn
+	limit time.Time
+}
+
+// withFirstByteDeadline bounds the handshake on conn by timeout, if it is positive
+func withFirstByteDeadline(conn net.Conn, timeout time.Duration) net.Conn {
+	if timeout <= 0 {
+		return conn
+	}
+	c := &firstByteConn{Conn: conn, limit: time.Now().Add(timeout)}
+	c.Conn.SetDeadline(c.limit)
+	return c
+}
+
+// SetDeadline implements net.Conn, keeping the first-byte limit until the deadline is cleared
+func (c *firstByteConn) SetDeadline(t time.Time) error {
+	if t.IsZero() {
+		c.limit = time.Time{}
+	} else if !c.limit.IsZero() && c.limit.Before(t) {
+		t = c.limit
+	}
+	return c.Conn.SetDeadline(t)
+}
+
+// clientTLSConfig returns the TLS configuration for a connection to host
+func (d *webSocketDialer) clientTLSConfig(host string) *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if d.tlsConfig != nil {
+		config = d.tlsConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	return config
+}
+
+// tunnel opens an HTTP CONNECT tunnel to addr through the proxy, over TLS if the proxy URL is https
+func (d *webSocketDialer) tunnel(ctx context.Context, network, addr string, proxyURL *url.URL, timeouts Timeouts) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := dialTimeout(ctx, network, proxyAddr, timeouts.Dial)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn, err := handshakeTLS(ctx, conn, d.clientTLSConfig(proxyURL.Hostname()), timeouts.TLSHandshake)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		connect.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := connect.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+
+	// The server sends nothing through the tunnel before the client speaks, so the reader buffers
+	// no tunneled bytes
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connect)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused tunnel to %s: %s", addr, resp.Status)
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// dialTimeout opens a TCP connection, returning a *TimeoutError if it takes longer than timeout
+func dialTimeout(ctx context.Context, network, addr string, timeout time.Duration) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil && timeout > 0 && ctx.Err() == nil && isTimeout(err) {
+		return nil, &TimeoutError{Phase: TimeoutPhaseDial, Limit: timeout, Err: err}
+	}
+	return conn, err
+}
+
+// handshakeTLS runs a TLS client handshake on conn, returning a *TimeoutError if it takes longer than timeout
+func handshakeTLS(ctx context.Context, conn net.Conn, config *tls.Config, timeout time.Duration) (*tls.Conn, error) {
+	handshakeCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		handshakeCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		if timeout > 0 && ctx.Err() == nil && handshakeCtx.Err() != nil {
+			return nil, &TimeoutError{Phase: TimeoutPhaseTLSHandshake, Limit: timeout, Err: err}
+		}
+		return nil, err
+	}
+	return tlsConn, nil
+}
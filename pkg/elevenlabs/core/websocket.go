@@ -2,9 +2,14 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,20 +23,53 @@ func (e *WebSocketError) Error() string {
 	return e.Message
 }
 
+// webSocketStats accumulates per-session counters for metrics
+type webSocketStats struct {
+	messagesSent     atomic.Int64
+	messagesReceived atomic.Int64
+	bytesReceived    atomic.Int64
+	characters       atomic.Int64
+	audio            atomic.Int64
+}
+
 // WebSocketClient handles WebSocket connections to the ElevenLabs API
 type WebSocketClient struct {
-	conn      *websocket.Conn
-	apiKey    string
-	baseURL   string
-	connected bool
-	mu        sync.RWMutex
+	conn        WebSocketConn
+	apiKey      string
+	credentials CredentialProvider
+	sessionKey  string
+	residency   *residencyPolicy
+	dialer      *webSocketDialer
+	timeouts    Timeouts
+	baseURL     string
+	userAgent   string
+	middleware  []Middleware
+	logger      *apiLogger
+	metrics     MetricsCollector
+	tracer      Tracer
+	span        Span
+	operation   Operation
+	limiter     *ConcurrencyLimiter
+	priority    Priority
+	release     func()
+	stats       webSocketStats
+	endpoint    string
+	openedAt    time.Time
+	connected   bool
+	mu          sync.RWMutex
+
+	// wsMiddleware wraps the dialer of the connection
+	wsMiddleware []WebSocketMiddleware
 }
 
 // NewWebSocketClient creates a new WebSocket client
 func NewWebSocketClient(baseURL, apiKey string) *WebSocketClient {
 	return &WebSocketClient{
-		apiKey:  apiKey,
-		baseURL: baseURL,
+		apiKey:   apiKey,
+		baseURL:  baseURL,
+		tracer:   NoopTracer{},
+		priority: PriorityDefault,
+		dialer:   newWebSocketDialer(nil),
 	}
 }
 
@@ -45,9 +83,22 @@ func (w *WebSocketClient) Connect(ctx context.Context, endpoint string, headers
 	}
 
 	// Prepare headers
+	apiKey := w.apiKey
+	if apiKey == "" && w.credentials != nil {
+		var err error
+		apiKey, err = w.credentials.APIKey(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to WebSocket: %w", err)
+		}
+	}
+
 	header := http.Header{}
-	if w.apiKey != "" {
-		header.Set(APIKeyHeader, w.apiKey)
+	if apiKey != "" {
+		header.Set(APIKeyHeader, apiKey)
+	}
+
+	if w.userAgent != "" {
+		header.Set("User-Agent", w.userAgent)
 	}
 
 	// Add custom headers
@@ -55,18 +106,116 @@ func (w *WebSocketClient) Connect(ctx context.Context, endpoint string, headers
 		header.Set(key, value)
 	}
 
-	// Create WebSocket connection
-	dialer := websocket.Dialer{
-		HandshakeTimeout: DefaultRetryConfig().MaxDelay,
+	url := w.baseURL + "/" + endpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket handshake request: %w", err)
+	}
+	req.Header = header
+
+	if err := w.residency.check(req.URL); err != nil {
+		return fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
-	url := w.baseURL + "/" + endpoint
-	conn, _, err := dialer.DialContext(ctx, url, header)
+	// Create WebSocket connection through the same proxy and TLS settings as HTTP requests
+	dialer, err := w.dialer.dialer(req.URL, w.timeouts)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
+	// The session span covers the handshake and lasts until the connection is closed
+	ctx, span := w.tracer.StartSpan(ctx, "elevenlabs.websocket", operationAttributes(http.MethodGet, req.URL.Path, w.operation)...)
+	req = req.WithContext(ctx)
+	if traceParent := span.TraceParent(); traceParent != "" {
+		req.Header.Set(TraceParentHeader, traceParent)
+	}
+
+	// The session holds a concurrency slot until it is closed
+	release := func() {}
+	if w.limiter != nil {
+		release, err = w.limiter.Acquire(ctx, w.priority)
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			return fmt.Errorf("failed to connect to WebSocket: %w", err)
+		}
+	}
+
+	// WebSocket middleware can record, replay or replace the connection
+	wsDialer := ChainWebSocket(WebSocketDialerFunc(func(ctx context.Context, rawURL string, header http.Header) (WebSocketConn, *http.Response, error) {
+		c, resp, err := dialer.DialContext(ctx, rawURL, header)
+		if c == nil {
+			return nil, resp, err
+		}
+		return c, resp, err
+	}), w.wsMiddleware...)
+
+	// Run the handshake through the middleware chain so it is observed like any other request
+	var conn WebSocketConn
+	dial := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		c, resp, err := wsDialer.DialWebSocket(req.Context(), req.URL.String(), req.Header)
+		if err != nil {
+			if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+				return resp, nil
+			}
+			return nil, err
+		}
+		conn = c
+		return resp, nil
+	})
+
+	start := time.Now()
+	resp, err := Chain(dial, w.middleware...).Do(req)
+	var timeoutErr *TimeoutError
+	if err != nil && w.timeouts.FirstByte > 0 && ctx.Err() == nil && !errors.As(err, &timeoutErr) &&
+		(isTimeout(err) || errors.Is(err, context.DeadlineExceeded)) {
+		// Dial and TLS timeouts are reported by the dialer, so the handshake response was too slow
+		err = &TimeoutError{Phase: TimeoutPhaseFirstByte, Limit: w.timeouts.FirstByte, Err: err}
+	}
+	if err != nil {
+		if conn != nil {
+			conn.Close()
+		}
+		release()
+		w.logger.logWebSocket(ctx, slog.LevelWarn, "connect_failed", req.URL.Path,
+			slog.Duration("latency", time.Since(start)), slog.String("error", err.Error()))
+		err = fmt.Errorf("failed to connect to WebSocket: %w", err)
+		span.RecordError(err)
+		span.End()
+		w.observeSession(req.URL.Path, time.Since(start), err)
+		return err
+	}
+	if conn == nil {
+		release()
+		if resp.StatusCode == http.StatusUnauthorized && w.apiKey == "" && w.credentials != nil {
+			invalidateCredentials(w.credentials, apiKey)
+		}
+		w.logger.logWebSocket(ctx, slog.LevelWarn, "connect_failed", req.URL.Path,
+			slog.Duration("latency", time.Since(start)), slog.Int("status", resp.StatusCode))
+		err = fmt.Errorf("failed to connect to WebSocket: handshake failed with status %s", resp.Status)
+		span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+		span.RecordError(err)
+		span.End()
+		w.observeSession(req.URL.Path, time.Since(start), err)
+		return err
+	}
+
+	w.logger.logWebSocket(ctx, slog.LevelDebug, "connected", req.URL.Path,
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("status", resp.StatusCode),
+		slog.String("request_id", resp.Header.Get(RequestIDHeader)))
+
+	span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+	if requestID := resp.Header.Get(RequestIDHeader); requestID != "" {
+		span.SetAttributes(Attr("elevenlabs.request_id", requestID))
+	}
+
 	w.conn = conn
+	w.sessionKey = apiKey
+	w.release = release
+	w.span = span
+	w.endpoint = req.URL.Path
+	w.openedAt = time.Now()
 	w.connected = true
 
 	return nil
@@ -81,7 +230,12 @@ func (w *WebSocketClient) Send(data interface{}) error {
 		return &WebSocketError{Message: "WebSocket not connected"}
 	}
 
-	return w.conn.WriteJSON(data)
+	message, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return w.writeMessage(websocket.TextMessage, message)
 }
 
 // SendText sends text data through the WebSocket connection
@@ -93,7 +247,7 @@ func (w *WebSocketClient) SendText(data string) error {
 		return &WebSocketError{Message: "WebSocket not connected"}
 	}
 
-	return w.conn.WriteMessage(websocket.TextMessage, []byte(data))
+	return w.writeMessage(websocket.TextMessage, []byte(data))
 }
 
 // SendBinary sends binary data through the WebSocket connection
@@ -105,7 +259,7 @@ func (w *WebSocketClient) SendBinary(data []byte) error {
 		return &WebSocketError{Message: "WebSocket not connected"}
 	}
 
-	return w.conn.WriteMessage(websocket.BinaryMessage, data)
+	return w.writeMessage(websocket.BinaryMessage, data)
 }
 
 // Receive receives data from the WebSocket connection
@@ -117,8 +271,7 @@ func (w *WebSocketClient) Receive() ([]byte, error) {
 		return nil, &WebSocketError{Message: "WebSocket not connected"}
 	}
 
-	_, message, err := w.conn.ReadMessage()
-	return message, err
+	return w.readMessage()
 }
 
 // ReceiveJSON receives JSON data from the WebSocket connection
@@ -130,7 +283,12 @@ func (w *WebSocketClient) ReceiveJSON(v interface{}) error {
 		return &WebSocketError{Message: "WebSocket not connected"}
 	}
 
-	return w.conn.ReadJSON(v)
+	message, err := w.readMessage()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(message, v)
 }
 
 // Close closes the WebSocket connection
@@ -145,10 +303,101 @@ func (w *WebSocketClient) Close() error {
 	err := w.conn.Close()
 	w.connected = false
 	w.conn = nil
+	w.release()
+
+	// The whole session is billed to the key it was opened with
+	if tracker, ok := w.credentials.(QuotaTracker); ok && w.apiKey == "" && w.sessionKey != "" {
+		if characters := w.stats.characters.Load(); characters > 0 {
+			tracker.RecordUsage(w.sessionKey, int(characters))
+		}
+	}
+
+	w.logger.logWebSocket(context.Background(), slog.LevelDebug, "closed", w.endpoint,
+		slog.Duration("duration", time.Since(w.openedAt)))
+	w.observeSession(w.endpoint, time.Since(w.openedAt), nil)
+
+	w.span.SetAttributes(
+		Attr("elevenlabs.messages_sent", w.stats.messagesSent.Load()),
+		Attr("elevenlabs.messages_received", w.stats.messagesReceived.Load()),
+	)
+	if err != nil {
+		w.span.RecordError(err)
+	}
+	w.span.End()
 
 	return err
 }
 
+// RecordCharacters adds submitted characters to the session metrics
+func (w *WebSocketClient) RecordCharacters(n int) {
+	w.stats.characters.Add(int64(n))
+}
+
+// RecordAudio adds generated audio playback time to the session metrics
+func (w *WebSocketClient) RecordAudio(duration time.Duration) {
+	w.stats.audio.Add(int64(duration))
+}
+
+// observeSession reports the session to the metrics collector, if one is configured
+func (w *WebSocketClient) observeSession(path string, duration time.Duration, err error) {
+	if w.metrics == nil {
+		return
+	}
+
+	endpoint := w.operation.Endpoint
+	if endpoint == "" {
+		endpoint = path
+	}
+
+	w.metrics.ObserveWebSocketSession(WebSocketMetrics{
+		Endpoint:            endpoint,
+		VoiceID:             w.operation.VoiceID,
+		ModelID:             w.operation.ModelID,
+		Duration:            duration,
+		MessagesSent:        w.stats.messagesSent.Load(),
+		MessagesReceived:    w.stats.messagesReceived.Load(),
+		BytesReceived:       w.stats.bytesReceived.Load(),
+		AudioSeconds:        time.Duration(w.stats.audio.Load()).Seconds(),
+		CharactersSubmitted: w.stats.characters.Load(),
+		Err:                 err,
+	})
+}
+
+// writeMessage writes a message to the connection; the caller must hold the lock
+func (w *WebSocketClient) writeMessage(messageType int, data []byte) error {
+	if err := w.conn.WriteMessage(messageType, data); err != nil {
+		w.logger.logWebSocket(context.Background(), slog.LevelWarn, "send_failed", w.endpoint, slog.String("error", err.Error()))
+		return err
+	}
+
+	w.stats.messagesSent.Add(1)
+	w.logger.logWebSocketMessage(context.Background(), "sent", w.endpoint, data)
+	return nil
+}
+
+// readMessage reads the next message from the connection; the caller must hold the lock
+func (w *WebSocketClient) readMessage() ([]byte, error) {
+	// A read that times out leaves the connection unusable, so the session fails fast when messages stop
+	if w.timeouts.ReadIdle > 0 {
+		w.conn.SetReadDeadline(time.Now().Add(w.timeouts.ReadIdle))
+	}
+	_, message, err := w.conn.ReadMessage()
+	if err != nil && w.timeouts.ReadIdle > 0 && isTimeout(err) {
+		err = &TimeoutError{Phase: TimeoutPhaseReadIdle, Limit: w.timeouts.ReadIdle, Err: err}
+	}
+	if err != nil {
+		w.logger.logWebSocket(context.Background(), slog.LevelDebug, "receive_ended", w.endpoint, slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if w.stats.messagesReceived.Add(1) == 1 {
+		w.span.SetAttributes(Attr("elevenlabs.time_to_first_message_ms", time.Since(w.openedAt).Milliseconds()))
+	}
+	w.stats.bytesReceived.Add(int64(len(message)))
+	w.logger.logWebSocketMessage(context.Background(), "received", w.endpoint, message)
+	return message, nil
+}
+
 // IsConnected returns whether the WebSocket is connected
 func (w *WebSocketClient) IsConnected() bool {
 	w.mu.RLock()
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WebSocketConn is the connection a WebSocketClient exchanges messages over. *websocket.Conn implements it.
+type WebSocketConn interface {
+	WriteMessage(messageType int, data []byte) error
+	ReadMessage() (messageType int, data []byte, err error)
+	SetReadDeadline(t time.Time) error
+	SetCloseHandler(handler func(code int, text string) error)
+	SetPingHandler(handler func(appData string) error)
+	SetPongHandler(handler func(appData string) error)
+	Close() error
+}
+
+// WebSocketDialer opens WebSocket connections. On a failed handshake it returns the response of the server,
+// if there was one, with a nil connection.
+type WebSocketDialer interface {
+	DialWebSocket(ctx context.Context, url string, header http.Header) (WebSocketConn, *http.Response, error)
+}
+
+// WebSocketDialerFunc adapts an ordinary function to the WebSocketDialer interface
+type WebSocketDialerFunc func(ctx context.Context, url string, header http.Header) (WebSocketConn, *http.Response, error)
+
+// DialWebSocket calls f(ctx, url, header)
+func (f WebSocketDialerFunc) DialWebSocket(ctx context.Context, url string, header http.Header) (WebSocketConn, *http.Response, error) {
+	return f(ctx, url, header)
+}
+
+// WebSocketMiddleware wraps the dialer of WebSocket sessions, for example to record or replay their messages
+type WebSocketMiddleware func(next WebSocketDialer) WebSocketDialer
+
+// ChainWebSocket wraps dialer with the given middleware so that the first middleware sees each dial first
+func ChainWebSocket(dialer WebSocketDialer, middleware ...WebSocketMiddleware) WebSocketDialer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			dialer = middleware[i](dialer)
+		}
+	}
+	return dialer
+}
@@ -0,0 +1,52 @@
+package elevenlabstest
+
+import (
+	"path"
+	"testing"
+)
+
+// RequestsTo returns the requests received with the given method whose path matches a path.Match pattern,
+// such as "/v1/voices/*". An empty method matches every method.
+func (s *Server) RequestsTo(method, pattern string) []Request {
+	var matches []Request
+	for _, req := range s.Requests() {
+		if method != "" && req.Method != method {
+			continue
+		}
+		if ok, _ := path.Match(pattern, req.Path); ok {
+			matches = append(matches, req)
+		}
+	}
+	return matches
+}
+
+// AssertRequested fails the test unless a matching request was received, and returns the last one
+func (s *Server) AssertRequested(t testing.TB, method, pattern string) Request {
+	t.Helper()
+
+	matches := s.RequestsTo(method, pattern)
+	if len(matches) == 0 {
+		t.Fatalf("elevenlabstest: expected a %s %s request, got none", method, pattern)
+		return Request{}
+	}
+	return matches[len(matches)-1]
+}
+
+// AssertRequestCount fails the test unless exactly n matching requests were received
+func (s *Server) AssertRequestCount(t testing.TB, method, pattern string, n int) {
+	t.Helper()
+
+	if got := len(s.RequestsTo(method, pattern)); got != n {
+		t.Errorf("elevenlabstest: expected %d %s %s requests, got %d", n, method, pattern, got)
+	}
+}
+
+// AssertHeader fails the test unless the last matching request carried a header with the given value
+func (s *Server) AssertHeader(t testing.TB, method, pattern, key, value string) {
+	t.Helper()
+
+	req := s.AssertRequested(t, method, pattern)
+	if got := req.Header.Get(key); got != value {
+		t.Errorf("elevenlabstest: expected header %s of %s %s to be %q, got %q", key, method, pattern, value, got)
+	}
+}
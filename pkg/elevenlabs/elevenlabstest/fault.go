@@ -0,0 +1,186 @@
+package elevenlabstest
+
+import (
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// Fault is a failure the server injects into the requests it matches
+type Fault struct {
+	// Method and Path select the requests the fault applies to. Path is a path.Match pattern such as
+	// "/v1/text-to-speech/*/stream". Empty fields match every request.
+	Method string
+	Path   string
+	// Times limits the fault to the next n matching requests; zero applies it to all of them
+	Times int
+
+	// Latency delays the response
+	Latency time.Duration
+	// ChunkLatency delays each audio chunk or WebSocket frame after the first
+	ChunkLatency time.Duration
+	// FirstByteLatency delays the first audio chunk of an HTTP response after its headers have been sent
+	FirstByteLatency time.Duration
+
+	// Status, if set, fails the request with an error response carrying Code
+	Status int
+	Code   string
+	// RetryAfter is sent in the Retry-After header of the error response
+	RetryAfter time.Duration
+
+	// Drop closes the connection without a response or, with DropAfter, after that many bytes of audio
+	Drop      bool
+	DropAfter int
+}
+
+// RateLimit returns a fault that rejects requests with 429 Too Many Requests
+func RateLimit(retryAfter time.Duration) Fault {
+	return Fault{
+		Status:     http.StatusTooManyRequests,
+		Code:       core.CodeTooManyConcurrentRequests,
+		RetryAfter: retryAfter,
+	}
+}
+
+// faultState is an injected fault with the number of requests it still applies to
+type faultState struct {
+	fault     Fault
+	remaining int
+}
+
+// Inject adds a fault. When several faults match a request, the one injected first applies.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{fault: fault, remaining: fault.Times})
+}
+
+// ClearFaults removes the injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// fault returns the fault applying to a request, or nil if there is none
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, state := range s.faults {
+		if !state.fault.matches(r) {
+			continue
+		}
+		if state.fault.Times > 0 {
+			state.remaining--
+			if state.remaining == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		fault := state.fault
+		return &fault
+	}
+	return nil
+}
+
+// matches reports whether the fault applies to a request
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	if f.Path != "" {
+		if ok, _ := path.Match(f.Path, r.URL.Path); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// apply delays, fails or drops a request as the fault requires, and reports whether it should still be served
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f == nil {
+		return true
+	}
+
+	if !sleep(r, f.Latency) {
+		return false
+	}
+	if f.Drop && f.DropAfter == 0 {
+		drop(w)
+		return false
+	}
+	if f.Status != 0 {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+		}
+		code := f.Code
+		if code == "" {
+			code = "injected_fault"
+		}
+		writeError(w, f.Status, code, "Injected fault")
+		return false
+	}
+	return true
+}
+
+// dropAfter returns the number of audio bytes to send before dropping the connection, or -1 to send them all
+func (f *Fault) dropAfter() int {
+	if f == nil || !f.Drop {
+		return -1
+	}
+	return f.DropAfter
+}
+
+// chunkLatency returns the delay between audio chunks
+func (f *Fault) chunkLatency() time.Duration {
+	if f == nil {
+		return 0
+	}
+	return f.ChunkLatency
+}
+
+// awaitFirstChunk sends the response headers and waits for the first-byte latency of a fault, reporting
+// whether the client is still waiting for the body
+func awaitFirstChunk(w http.ResponseWriter, r *http.Request, fault *Fault) bool {
+	if fault == nil || fault.FirstByteLatency <= 0 {
+		return true
+	}
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return sleep(r, fault.FirstByteLatency)
+}
+
+// sleep waits for d and reports whether the client is still waiting for the response
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// drop closes the connection of a response, flushing whatever was written to it
+func drop(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return
+	}
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	buffer.Flush()
+	conn.Close()
+}
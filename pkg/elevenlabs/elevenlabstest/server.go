@@ -0,0 +1,238 @@
+// Package elevenlabstest provides an in-process fake of the ElevenLabs API for tests. It serves text-to-speech,
+// voices and the stream-input WebSocket with synthetic audio, records the requests it receives and can inject
+// errors, latency and dropped connections.
+//
+//	server := elevenlabstest.NewServer(t)
+//	client := server.Client()
+//
+//	audio, err := client.TextToSpeech.Convert(ctx, text_to_speech.ConvertRequest{
+//	    VoiceID: elevenlabstest.DefaultVoiceID,
+//	    Text:    "Hello",
+//	})
+//	server.AssertRequestCount(t, "POST", "/v1/text-to-speech/*", 1)
+package elevenlabstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/voices"
+)
+
+// APIKey is the key used by clients created with Server.Client
+const APIKey = "test-api-key"
+
+// DefaultVoiceID is the voice every server starts with
+const DefaultVoiceID = "JBFqnCBsd6RMkjVDRZzb"
+
+// Server is a fake ElevenLabs API listening on a local port
+type Server struct {
+	// URL is the base URL of the server, such as http://127.0.0.1:50123
+	URL string
+
+	t      testing.TB
+	server *httptest.Server
+
+	mu        sync.Mutex
+	apiKey    string
+	requests  []*Request
+	faults    []*faultState
+	voices    map[string]*voices.Voice
+	voiceIDs  []string
+	version   int
+	nextID    int
+	requestID int
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Messages holds the messages a WebSocket client sent, in order
+	Messages [][]byte
+}
+
+// DecodeJSON decodes the request body into v
+func (r Request) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// NewServer starts a server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:      t,
+		voices: make(map[string]*voices.Voice),
+	}
+	s.AddVoice(voices.Voice{
+		VoiceID:  DefaultVoiceID,
+		Name:     "George",
+		Category: "premade",
+	})
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Environment returns the environment that points clients at the server
+func (s *Server) Environment() core.Environment {
+	return core.Environment{BaseURL: s.URL}
+}
+
+// Client creates a client of the server. opts are applied after the environment and API key, so they can
+// override them.
+func (s *Server) Client(opts ...elevenlabs.Option) *elevenlabs.Client {
+	s.t.Helper()
+
+	opts = append([]elevenlabs.Option{elevenlabs.WithEnvironment(s.Environment())}, opts...)
+	client, err := elevenlabs.NewClient(APIKey, opts...)
+	if err != nil {
+		s.t.Fatalf("elevenlabstest: failed to create client: %v", err)
+	}
+	return client
+}
+
+// SetAPIKey makes the server reject requests that do not carry apiKey. By default any non-empty key is accepted.
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]Request, len(s.requests))
+	for i, req := range s.requests {
+		requests[i] = *req
+		requests[i].Messages = append([][]byte(nil), req.Messages...)
+	}
+	return requests
+}
+
+// Reset forgets the requests received and removes the injected faults. Voices are kept.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.faults = nil
+}
+
+// serveHTTP records a request, applies the faults matching it and routes it
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := s.record(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	w.Header().Set(core.RequestIDHeader, s.newRequestID())
+
+	fault := s.fault(r)
+	if !fault.apply(w, r) {
+		return
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, core.CodeInvalidAPIKey, "Invalid API key")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "v1" && segments[1] == "text-to-speech":
+		s.serveTextToSpeech(w, r, req, fault, segments[2:])
+	case len(segments) >= 2 && segments[1] == "voices" && (segments[0] == "v1" || segments[0] == "v2"):
+		s.serveVoices(w, r, segments)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// record stores a request, leaving its body readable by the handlers
+func (s *Server) record(r *http.Request) (*Request, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	req := &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	return req, nil
+}
+
+// recordMessage appends a message a WebSocket client sent to its request
+func (s *Server) recordMessage(req *Request, message []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req.Messages = append(req.Messages, message)
+}
+
+// authorized reports whether a request carries an accepted API key
+func (s *Server) authorized(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apiKey := r.Header.Get(core.APIKeyHeader)
+	if s.apiKey != "" {
+		return apiKey == s.apiKey
+	}
+	return apiKey != ""
+}
+
+// newRequestID returns the ID of the next response
+func (s *Server) newRequestID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requestID++
+	return fmt.Sprintf("req-%d", s.requestID)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the shape the API uses
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"detail": map[string]string{
+			"status":  code,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,260 @@
+package elevenlabstest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/cache"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/elevenlabstest"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/text_to_speech"
+)
+
+// convertRequest returns a request for text spoken by the default voice
+func convertRequest(text string) text_to_speech.ConvertRequest {
+	return text_to_speech.ConvertRequest{VoiceID: elevenlabstest.DefaultVoiceID, Text: text}
+}
+
+func TestStreamWithTimestamps(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	client := server.Client()
+
+	chunks, err := client.TextToSpeech.StreamWithTimestamps(context.Background(), text_to_speech.StreamRequest{
+		VoiceID: elevenlabstest.DefaultVoiceID,
+		Text:    "Hello there",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var final bool
+	for chunk := range chunks {
+		final = chunk.IsFinal
+	}
+	if !final {
+		t.Error("expected the last chunk to be final")
+	}
+	server.AssertRequested(t, http.MethodPost, "/v1/text-to-speech/*/stream/with-timestamps")
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	server.Inject(elevenlabstest.Fault{Path: "/v1/text-to-speech/*", Latency: 50 * time.Millisecond})
+
+	// Count the requests in flight below the limiter
+	var inFlight, peak atomic.Int32
+	counter := func(next core.Doer) core.Doer {
+		return core.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			return next.Do(req)
+		})
+	}
+	client := server.Client(elevenlabs.WithConcurrencyLimit(2), elevenlabs.WithMiddleware(counter))
+
+	texts := []string{"one", "two", "three", "four", "five", "six"}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(texts))
+	for _, text := range texts {
+		wg.Add(1)
+		go func(text string) {
+			defer wg.Done()
+			_, err := client.TextToSpeech.Convert(context.Background(), convertRequest(text))
+			errs <- err
+		}(text)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", got)
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*", len(texts))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	server.Inject(elevenlabstest.Fault{Path: "/v1/text-to-speech/*", Status: http.StatusServiceUnavailable})
+
+	var mu sync.Mutex
+	var states []core.CircuitState
+	client := server.Client(
+		elevenlabs.WithRetryConfig(core.RetryConfig{}),
+		elevenlabs.WithCircuitBreaker(core.CircuitBreakerConfig{
+			FailureThreshold: 2,
+			Cooldown:         100 * time.Millisecond,
+			OnStateChange: func(family string, from, to core.CircuitState) {
+				mu.Lock()
+				defer mu.Unlock()
+				states = append(states, to)
+			},
+		}),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var serverErr *elevenlabs.ServerError
+		if _, err := client.TextToSpeech.Convert(ctx, convertRequest("Hello")); !errors.As(err, &serverErr) {
+			t.Fatalf("expected a server error, got %v", err)
+		}
+	}
+	if _, err := client.TextToSpeech.Convert(ctx, convertRequest("Hello")); !errors.Is(err, elevenlabs.ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*", 2)
+
+	// The probe after the cooldown closes the circuit again
+	server.ClearFaults()
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.TextToSpeech.Convert(ctx, convertRequest("Hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []core.CircuitState{core.CircuitOpen, core.CircuitHalfOpen, core.CircuitClosed}
+	if len(states) != len(want) {
+		t.Fatalf("expected state changes %v, got %v", want, states)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("expected state changes %v, got %v", want, states)
+		}
+	}
+}
+
+func TestHedgingOnFirstByte(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	// The original stream sends its headers at once but stalls before the audio
+	server.Inject(elevenlabstest.Fault{Path: "/v1/text-to-speech/*/stream", Times: 1, FirstByteLatency: 5 * time.Second})
+	client := server.Client(elevenlabs.WithTTSHedging(elevenlabs.HedgePolicy{Delay: 50 * time.Millisecond}))
+
+	start := time.Now()
+	chunks, err := client.TextToSpeech.Stream(context.Background(), text_to_speech.StreamRequest{
+		VoiceID: elevenlabstest.DefaultVoiceID,
+		Text:    "Hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audio []byte
+	for chunk := range chunks {
+		audio = append(audio, chunk...)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the duplicate to win, took %s", elapsed)
+	}
+	if !bytes.Equal(audio, elevenlabstest.SyntheticAudio(elevenlabstest.DefaultVoiceID, "Hello")) {
+		t.Error("unexpected audio")
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*/stream", 2)
+}
+
+func TestHedgingSkipsRejectedKey(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	server.SetAPIKey("alternate-key")
+	client := server.Client(elevenlabs.WithTTSHedging(elevenlabs.HedgePolicy{
+		Delay:      20 * time.Millisecond,
+		Alternates: []elevenlabs.HedgeTarget{{APIKey: "alternate-key"}},
+	}))
+
+	audio, err := client.TextToSpeech.Convert(context.Background(), convertRequest("Hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(audio, elevenlabstest.SyntheticAudio(elevenlabstest.DefaultVoiceID, "Hello")) {
+		t.Error("unexpected audio")
+	}
+}
+
+func TestHedgingReleasesLosingRequests(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	client := server.Client(
+		elevenlabs.WithConcurrencyLimit(2),
+		elevenlabs.WithRetryConfig(core.RetryConfig{}),
+		elevenlabs.WithTTSHedging(elevenlabs.HedgePolicy{}),
+	)
+
+	// One request of each call fails; a leaked slot would block the calls that follow
+	for i := 0; i < 5; i++ {
+		server.Inject(elevenlabstest.Fault{Path: "/v1/text-to-speech/*", Times: 1, Status: http.StatusInternalServerError})
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		_, err := client.TextToSpeech.Convert(ctx, convertRequest("Hello"))
+		cancel()
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+}
+
+func TestCacheSharesConcurrentRequests(t *testing.T) {
+	server := elevenlabstest.NewServer(t)
+	server.Inject(elevenlabstest.Fault{Path: "/v1/text-to-speech/*", Latency: 100 * time.Millisecond})
+	client := server.Client(elevenlabs.WithTTSCache(cache.NewMemoryCache(1<<20), time.Minute))
+	want := elevenlabstest.SyntheticAudio(elevenlabstest.DefaultVoiceID, "Hello")
+
+	var wg sync.WaitGroup
+	results := make(chan []byte, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			audio, err := client.TextToSpeech.Convert(context.Background(), convertRequest("Hello"))
+			if err != nil {
+				t.Error(err)
+			}
+			results <- audio
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	for audio := range results {
+		if !bytes.Equal(audio, want) {
+			t.Error("unexpected audio")
+		}
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*", 1)
+
+	// Streams replay the cached audio
+	resp, err := client.TextToSpeech.WithRawResponse.Stream(context.Background(), text_to_speech.StreamRequest{
+		VoiceID: elevenlabstest.DefaultVoiceID,
+		Text:    "Hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audio []byte
+	for chunk := range resp.Data {
+		audio = append(audio, chunk...)
+	}
+	if !resp.Cached || !bytes.Equal(audio, want) || resp.Err() != nil {
+		t.Errorf("expected the cached audio, got %d bytes (cached %v, err %v)", len(audio), resp.Cached, resp.Err())
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*/stream", 0)
+
+	// Requests with another key are not served audio cached for the client's key
+	if _, err := client.TextToSpeech.Convert(context.Background(), convertRequest("Hello"), core.WithAPIKey("other-key")); err != nil {
+		t.Fatal(err)
+	}
+	server.AssertRequestCount(t, http.MethodPost, "/v1/text-to-speech/*", 2)
+}
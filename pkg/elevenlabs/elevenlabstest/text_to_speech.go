@@ -0,0 +1,181 @@
+package elevenlabstest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/text_to_speech"
+)
+
+// BytesPerCharacter is the size of the synthetic audio generated for each character of text
+const BytesPerCharacter = 64
+
+// CharacterDuration is the duration of each character in the synthetic alignment
+const CharacterDuration = 50 * time.Millisecond
+
+// StreamChunkSize is the size of the audio chunks sent by streaming endpoints
+const StreamChunkSize = 256
+
+// SyntheticAudio returns the audio the server generates for text spoken by a voice. It is deterministic,
+// so tests can compare it with what a client received.
+func SyntheticAudio(voiceID, text string) []byte {
+	seed := sha256.Sum256([]byte(voiceID + "\x00" + text))
+	audio := make([]byte, len([]rune(text))*BytesPerCharacter)
+	for i := range audio {
+		audio[i] = seed[i%len(seed)]
+	}
+	return audio
+}
+
+// SyntheticAlignment returns the timing of the synthetic audio of text
+func SyntheticAlignment(text string) *text_to_speech.Alignment {
+	alignment := &text_to_speech.Alignment{
+		Characters:                 []string{},
+		CharacterStartTimesSeconds: []float64{},
+		CharacterEndTimesSeconds:   []float64{},
+	}
+	for i, char := range []rune(text) {
+		alignment.Characters = append(alignment.Characters, string(char))
+		alignment.CharacterStartTimesSeconds = append(alignment.CharacterStartTimesSeconds, float64(i)*CharacterDuration.Seconds())
+		alignment.CharacterEndTimesSeconds = append(alignment.CharacterEndTimesSeconds, float64(i+1)*CharacterDuration.Seconds())
+	}
+	return alignment
+}
+
+// serveTextToSpeech routes the text-to-speech endpoints; segments follow /v1/text-to-speech
+func (s *Server) serveTextToSpeech(w http.ResponseWriter, r *http.Request, req *Request, fault *Fault, segments []string) {
+	voiceID, endpoint := segments[0], strings.Join(segments[1:], "/")
+
+	if endpoint == "stream-input" {
+		if !s.hasVoice(voiceID) {
+			writeError(w, http.StatusNotFound, core.CodeVoiceNotFound, fmt.Sprintf("Voice %s not found", voiceID))
+			return
+		}
+		s.serveStreamInput(w, r, req, fault, voiceID)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed")
+		return
+	}
+
+	// Validate the request
+	var body text_to_speech.ConvertRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if body.Text == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Text is required")
+		return
+	}
+	if !s.hasVoice(voiceID) {
+		writeError(w, http.StatusNotFound, core.CodeVoiceNotFound, fmt.Sprintf("Voice %s not found", voiceID))
+		return
+	}
+
+	audio := SyntheticAudio(voiceID, body.Text)
+	w.Header().Set(core.CharacterCostHeader, strconv.Itoa(len([]rune(body.Text))))
+
+	switch endpoint {
+	case "":
+		w.Header().Set("Content-Type", audioContentType(r.URL.Query().Get("output_format")))
+		writeAudio(w, r, fault, audio, len(audio))
+	case "stream":
+		w.Header().Set("Content-Type", audioContentType(r.URL.Query().Get("output_format")))
+		writeAudio(w, r, fault, audio, StreamChunkSize)
+	case "with-timestamps":
+		if fault.dropAfter() >= 0 {
+			drop(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, text_to_speech.TimestampResponse{
+			AudioBase64: base64.StdEncoding.EncodeToString(audio),
+			Alignment:   SyntheticAlignment(body.Text),
+		})
+	case "stream/with-timestamps":
+		writeTimestampChunks(w, r, fault, audio)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// writeAudio sends audio in chunks of size, applying the chunk latency and dropped connection of a fault
+func writeAudio(w http.ResponseWriter, r *http.Request, fault *Fault, audio []byte, size int) {
+	limit := fault.dropAfter()
+	flusher, _ := w.(http.Flusher)
+	if !awaitFirstChunk(w, r, fault) {
+		return
+	}
+
+	for offset := 0; offset < len(audio); offset += size {
+		if offset > 0 && !sleep(r, fault.chunkLatency()) {
+			return
+		}
+
+		chunk := audio[offset:min(offset+size, len(audio))]
+		if limit >= 0 && offset+len(chunk) >= limit {
+			w.Write(chunk[:limit-offset])
+			drop(w)
+			return
+		}
+		w.Write(chunk)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// writeTimestampChunks streams audio as JSON lines carrying the timing of their characters
+func writeTimestampChunks(w http.ResponseWriter, r *http.Request, fault *Fault, audio []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	limit := fault.dropAfter()
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	if !awaitFirstChunk(w, r, fault) {
+		return
+	}
+
+	for offset := 0; offset < len(audio); offset += StreamChunkSize {
+		if offset > 0 && !sleep(r, fault.chunkLatency()) {
+			return
+		}
+		if limit >= 0 && offset >= limit {
+			drop(w)
+			return
+		}
+
+		end := min(offset+StreamChunkSize, len(audio))
+		start := float64(offset/BytesPerCharacter) * CharacterDuration.Seconds()
+		stop := float64(end/BytesPerCharacter) * CharacterDuration.Seconds()
+		encoder.Encode(text_to_speech.TimestampChunk{
+			AudioBase64:             base64.StdEncoding.EncodeToString(audio[offset:end]),
+			CharacterStartTimestamp: &start,
+			CharacterEndTimestamp:   &stop,
+			IsFinal:                 end == len(audio),
+		})
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// audioContentType returns the content type of an output format
+func audioContentType(outputFormat string) string {
+	switch {
+	case strings.HasPrefix(outputFormat, "pcm_"):
+		return "audio/pcm"
+	case strings.HasPrefix(outputFormat, "ulaw_"):
+		return "audio/basic"
+	default:
+		return "audio/mpeg"
+	}
+}
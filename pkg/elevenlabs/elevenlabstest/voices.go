@@ -0,0 +1,237 @@
+package elevenlabstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/voices"
+)
+
+// defaultSearchPageSize is the page size of voice searches that do not set one
+const defaultSearchPageSize = 10
+
+// defaultVoiceSettings are the settings of voices that have none
+func defaultVoiceSettings() voices.VoiceSettings {
+	stability, similarityBoost, style, speakerBoost := 0.5, 0.75, 0.0, true
+	return voices.VoiceSettings{
+		Stability:       &stability,
+		SimilarityBoost: &similarityBoost,
+		Style:           &style,
+		UseSpeakerBoost: &speakerBoost,
+	}
+}
+
+// AddVoice adds a voice to the server and returns its ID, generating one if voice.VoiceID is empty
+func (s *Server) AddVoice(voice voices.Voice) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addVoice(voice)
+}
+
+// Voice returns the voice with the given ID as the server holds it, for example to check edited settings
+func (s *Server) Voice(voiceID string) (voices.Voice, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	voice, ok := s.voices[voiceID]
+	if !ok {
+		return voices.Voice{}, false
+	}
+	return *voice, true
+}
+
+// addVoice adds a voice; the caller must hold the lock
+func (s *Server) addVoice(voice voices.Voice) string {
+	if voice.VoiceID == "" {
+		s.nextID++
+		voice.VoiceID = fmt.Sprintf("voice-%d", s.nextID)
+	}
+	if _, ok := s.voices[voice.VoiceID]; !ok {
+		s.voiceIDs = append(s.voiceIDs, voice.VoiceID)
+	}
+	s.voices[voice.VoiceID] = &voice
+	s.version++
+	return voice.VoiceID
+}
+
+// hasVoice reports whether the server holds a voice
+func (s *Server) hasVoice(voiceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.voices[voiceID]
+	return ok
+}
+
+// listVoices returns the voices in the order they were added, with the ETag of the list
+func (s *Server) listVoices() ([]voices.Voice, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]voices.Voice, 0, len(s.voiceIDs))
+	for _, voiceID := range s.voiceIDs {
+		list = append(list, *s.voices[voiceID])
+	}
+	return list, fmt.Sprintf(`"voices-%d"`, s.version)
+}
+
+// serveVoices routes the voice endpoints; segments is the whole path
+func (s *Server) serveVoices(w http.ResponseWriter, r *http.Request, segments []string) {
+	route := r.Method + " " + strings.Join(segments, "/")
+	switch {
+	case route == "GET v1/voices":
+		s.serveListVoices(w, r)
+	case route == "GET v2/voices":
+		s.serveSearchVoices(w, r)
+	case route == "POST v1/voices/add":
+		s.serveAddVoice(w, r)
+	case route == "GET v1/voices/settings/default":
+		writeJSON(w, http.StatusOK, defaultVoiceSettings())
+	case segments[0] == "v1" && len(segments) >= 3:
+		s.serveVoice(w, r, segments[2], r.Method+" "+strings.Join(segments[3:], "/"))
+	default:
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// serveVoice serves the endpoints of a single voice; route is the method and the path after the voice ID
+func (s *Server) serveVoice(w http.ResponseWriter, r *http.Request, voiceID, route string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	voice, ok := s.voices[voiceID]
+	if !ok {
+		writeError(w, http.StatusNotFound, core.CodeVoiceNotFound, fmt.Sprintf("Voice %s not found", voiceID))
+		return
+	}
+
+	switch route {
+	case "GET ":
+		writeJSON(w, http.StatusOK, voice)
+	case "DELETE ":
+		delete(s.voices, voiceID)
+		for i, id := range s.voiceIDs {
+			if id == voiceID {
+				s.voiceIDs = append(s.voiceIDs[:i:i], s.voiceIDs[i+1:]...)
+				break
+			}
+		}
+		s.version++
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	case "POST edit":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid form: %v", err))
+			return
+		}
+		if name := r.FormValue("name"); name != "" {
+			voice.Name = name
+		}
+		if description := r.FormValue("description"); description != "" {
+			voice.Description = description
+		}
+		if labels := r.FormValue("labels"); labels != "" {
+			// Replace the map, since copies returned by Voice share it
+			var decoded map[string]string
+			if err := json.Unmarshal([]byte(labels), &decoded); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid labels: %v", err))
+				return
+			}
+			voice.Labels = decoded
+		}
+		s.version++
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	case "GET settings":
+		settings := defaultVoiceSettings()
+		if voice.Settings != nil {
+			settings = *voice.Settings
+		}
+		writeJSON(w, http.StatusOK, settings)
+	case "POST settings/edit":
+		var settings voices.VoiceSettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+		voice.Settings = &settings
+		s.version++
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	default:
+		writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// serveListVoices lists every voice, answering 304 Not Modified when the client holds the current list
+func (s *Server) serveListVoices(w http.ResponseWriter, r *http.Request) {
+	list, etag := s.listVoices()
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, voices.VoicesResponse{Voices: list})
+}
+
+// serveSearchVoices lists the voices whose name or category contain the search query, one page at a time
+func (s *Server) serveSearchVoices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	list, _ := s.listVoices()
+
+	// Filter the voices
+	search := strings.ToLower(query.Get("search"))
+	matches := make([]voices.Voice, 0, len(list))
+	for _, voice := range list {
+		if strings.Contains(strings.ToLower(voice.Name), search) || strings.Contains(strings.ToLower(voice.Category), search) {
+			matches = append(matches, voice)
+		}
+	}
+
+	// Select the page; the token is the offset of its first voice
+	pageSize := defaultSearchPageSize
+	if size, err := strconv.Atoi(query.Get("page_size")); err == nil && size > 0 {
+		pageSize = size
+	}
+	offset, _ := strconv.Atoi(query.Get("next_page_token"))
+	offset = min(max(offset, 0), len(matches))
+	end := min(offset+pageSize, len(matches))
+
+	resp := voices.SearchResponse{
+		Voices:     matches[offset:end],
+		HasMore:    end < len(matches),
+		TotalCount: len(matches),
+	}
+	if resp.HasMore {
+		token := strconv.Itoa(end)
+		resp.NextPageToken = &token
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// serveAddVoice creates a voice from the name, description and labels of a multipart form
+func (s *Server) serveAddVoice(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid form: %v", err))
+		return
+	}
+
+	voice := voices.Voice{
+		Name:        r.FormValue("name"),
+		Description: r.FormValue("description"),
+		Category:    "cloned",
+	}
+	if voice.Name == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Name is required")
+		return
+	}
+	if labels := r.FormValue("labels"); labels != "" {
+		if err := json.Unmarshal([]byte(labels), &voice.Labels); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("Invalid labels: %v", err))
+			return
+		}
+	}
+
+	voiceID := s.AddVoice(voice)
+	writeJSON(w, http.StatusOK, map[string]string{"voice_id": voiceID})
+}
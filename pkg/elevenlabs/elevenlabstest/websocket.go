@@ -0,0 +1,101 @@
+package elevenlabstest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// upgrader accepts the WebSocket connections of clients on any origin
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamInputMessage is a message a client sends over the stream-input WebSocket
+type streamInputMessage struct {
+	Text *string `json:"text"`
+}
+
+// streamInputFrame is a message the server sends over the stream-input WebSocket
+type streamInputFrame struct {
+	Audio               *string               `json:"audio"`
+	IsFinal             bool                  `json:"isFinal"`
+	Alignment           *streamInputAlignment `json:"alignment,omitempty"`
+	NormalizedAlignment *streamInputAlignment `json:"normalizedAlignment,omitempty"`
+}
+
+// streamInputAlignment is the timing of the characters of a frame, relative to the start of the session
+type streamInputAlignment struct {
+	Chars            []string `json:"chars"`
+	CharStartTimesMs []int    `json:"charStartTimesMs"`
+	CharDurationsMs  []int    `json:"charDurationsMs"`
+}
+
+// serveStreamInput speaks the stream-input protocol: each text message is answered with a frame carrying its
+// synthetic audio, and the empty text that ends the input with a final frame, after which the server closes the
+// connection. Messages of only whitespace, such as the initial " ", produce no audio.
+func (s *Server) serveStreamInput(w http.ResponseWriter, r *http.Request, req *Request, fault *Fault, voiceID string) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	limit := fault.dropAfter()
+	sent, elapsedMs := 0, 0
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.recordMessage(req, data)
+
+		var message streamInputMessage
+		if err := json.Unmarshal(data, &message); err != nil || message.Text == nil {
+			continue
+		}
+
+		// The empty text ends the input
+		if *message.Text == "" {
+			conn.WriteJSON(streamInputFrame{IsFinal: true})
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+		if strings.TrimSpace(*message.Text) == "" {
+			continue
+		}
+
+		if sent > 0 && !sleep(r, fault.chunkLatency()) {
+			return
+		}
+		audio := SyntheticAudio(voiceID, *message.Text)
+		if limit >= 0 && sent+len(audio) >= limit {
+			conn.UnderlyingConn().Close()
+			return
+		}
+
+		// Describe the timing of the characters
+		alignment := &streamInputAlignment{}
+		durationMs := int(CharacterDuration.Milliseconds())
+		for _, char := range *message.Text {
+			alignment.Chars = append(alignment.Chars, string(char))
+			alignment.CharStartTimesMs = append(alignment.CharStartTimesMs, elapsedMs)
+			alignment.CharDurationsMs = append(alignment.CharDurationsMs, durationMs)
+			elapsedMs += durationMs
+		}
+
+		encoded := base64.StdEncoding.EncodeToString(audio)
+		if err := conn.WriteJSON(streamInputFrame{
+			Audio:               &encoded,
+			Alignment:           alignment,
+			NormalizedAlignment: alignment,
+		}); err != nil {
+			return
+		}
+		sent += len(audio)
+	}
+}
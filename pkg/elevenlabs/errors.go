@@ -1,160 +1,161 @@
 package elevenlabs
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
 )
 
+// ErrCircuitOpen is matched by errors.Is when a request was rejected by an open circuit breaker
+var ErrCircuitOpen = core.ErrCircuitOpen
+
+// CircuitOpenError is returned instead of sending a request while the circuit for its endpoint family is open
+type CircuitOpenError = core.CircuitOpenError
+
+// ErrResidencyViolation is matched by errors.Is when a request was refused by strict data residency
+var ErrResidencyViolation = core.ErrResidencyViolation
+
+// ResidencyError is returned instead of sending a request or dialing a WebSocket outside the configured region
+type ResidencyError = core.ResidencyError
+
+// TimeoutError is returned when a connection phase, such as waiting for the next bytes of a stream, times out
+type TimeoutError = core.TimeoutError
+
 // ElevenLabsError represents an error from the ElevenLabs API
-type ElevenLabsError interface {
-	error
-	StatusCode() int
-	Body() interface{}
-}
+type ElevenLabsError = core.ElevenLabsError
 
 // APIError represents a generic API error
-type APIError struct {
-	statusCode int
-	body       interface{}
-	message    string
-}
+type APIError = core.APIError
 
-// Error implements the error interface
-func (e *APIError) Error() string {
-	if e.message != "" {
-		return e.message
-	}
-	return fmt.Sprintf("API error with status code %d", e.statusCode)
-}
+// BadRequestError represents a 400 Bad Request error
+type BadRequestError = core.BadRequestError
 
-// StatusCode returns the HTTP status code
-func (e *APIError) StatusCode() int {
-	return e.statusCode
-}
+// UnauthorizedError represents a 401 Unauthorized error
+type UnauthorizedError = core.UnauthorizedError
 
-// Body returns the error response body
-func (e *APIError) Body() interface{} {
-	return e.body
-}
+// ForbiddenError represents a 403 Forbidden error
+type ForbiddenError = core.ForbiddenError
 
-// BadRequestError represents a 400 Bad Request error
-type BadRequestError struct {
-	*APIError
-}
+// NotFoundError represents a 404 Not Found error
+type NotFoundError = core.NotFoundError
+
+// UnprocessableEntityError represents a 422 Unprocessable Entity error
+type UnprocessableEntityError = core.UnprocessableEntityError
+
+// ValidationErrorDetail is one entry of the detail list of a 422 response
+type ValidationErrorDetail = core.ValidationErrorDetail
+
+// TooEarlyError represents a 425 Too Early error
+type TooEarlyError = core.TooEarlyError
+
+// TooManyRequestsError represents a 429 Too Many Requests error
+type TooManyRequestsError = core.TooManyRequestsError
+
+// ServerError represents a 5xx error returned by the API
+type ServerError = core.ServerError
+
+// ErrorResponse represents a structured error response from the API
+type ErrorResponse = core.ErrorResponse
+
+// ErrorDetail is the detail field of an error response, including its machine-readable status
+type ErrorDetail = core.ErrorDetail
+
+// Sentinel errors matched by errors.Is against API errors carrying the corresponding detail.status code
+var (
+	ErrQuotaExceeded             = core.ErrQuotaExceeded
+	ErrVoiceNotFound             = core.ErrVoiceNotFound
+	ErrTooManyConcurrentRequests = core.ErrTooManyConcurrentRequests
+	ErrSystemBusy                = core.ErrSystemBusy
+	ErrInvalidAPIKey             = core.ErrInvalidAPIKey
+	ErrMaxCharacterLimitExceeded = core.ErrMaxCharacterLimitExceeded
+)
 
 // NewBadRequestError creates a new BadRequestError
 func NewBadRequestError(body interface{}) *BadRequestError {
-	return &BadRequestError{
-		APIError: &APIError{
-			statusCode: 400,
-			body:       body,
-			message:    "Bad Request",
-		},
-	}
+	return core.NewBadRequestError(body)
 }
 
-// ForbiddenError represents a 403 Forbidden error
-type ForbiddenError struct {
-	*APIError
+// NewUnauthorizedError creates a new UnauthorizedError
+func NewUnauthorizedError(body interface{}) *UnauthorizedError {
+	return core.NewUnauthorizedError(body)
 }
 
 // NewForbiddenError creates a new ForbiddenError
 func NewForbiddenError(body interface{}) *ForbiddenError {
-	return &ForbiddenError{
-		APIError: &APIError{
-			statusCode: 403,
-			body:       body,
-			message:    "Forbidden",
-		},
-	}
-}
-
-// NotFoundError represents a 404 Not Found error
-type NotFoundError struct {
-	*APIError
+	return core.NewForbiddenError(body)
 }
 
 // NewNotFoundError creates a new NotFoundError
 func NewNotFoundError(body interface{}) *NotFoundError {
-	return &NotFoundError{
-		APIError: &APIError{
-			statusCode: 404,
-			body:       body,
-			message:    "Not Found",
-		},
-	}
-}
-
-// UnprocessableEntityError represents a 422 Unprocessable Entity error
-type UnprocessableEntityError struct {
-	*APIError
+	return core.NewNotFoundError(body)
 }
 
 // NewUnprocessableEntityError creates a new UnprocessableEntityError
 func NewUnprocessableEntityError(body interface{}) *UnprocessableEntityError {
-	return &UnprocessableEntityError{
-		APIError: &APIError{
-			statusCode: 422,
-			body:       body,
-			message:    "Unprocessable Entity",
-		},
-	}
+	return core.NewUnprocessableEntityError(body)
 }
 
-// ErrorResponse represents a structured error response from the API
-type ErrorResponse struct {
-	Detail  interface{} `json:"detail,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+// NewTooEarlyError creates a new TooEarlyError
+func NewTooEarlyError(body interface{}) *TooEarlyError {
+	return core.NewTooEarlyError(body)
+}
+
+// NewTooManyRequestsError creates a new TooManyRequestsError
+func NewTooManyRequestsError(body interface{}) *TooManyRequestsError {
+	return core.NewTooManyRequestsError(body)
+}
+
+// NewServerError creates a new ServerError for the given 5xx status code
+func NewServerError(statusCode int, body interface{}) *ServerError {
+	return core.NewServerError(statusCode, body)
 }
 
 // ParseAPIError parses an HTTP response and returns an appropriate error
 func ParseAPIError(resp *http.Response) error {
-	if resp.StatusCode < 400 {
-		return nil
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return &APIError{
-			statusCode: resp.StatusCode,
-			message:    fmt.Sprintf("Failed to read error response: %v", err),
-		}
-	}
-
-	var errorResp ErrorResponse
-	if err := json.Unmarshal(body, &errorResp); err != nil {
-		// If we can't parse as JSON, use the raw body
-		return &APIError{
-			statusCode: resp.StatusCode,
-			body:       string(body),
-			message:    fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
-		}
-	}
-
-	switch resp.StatusCode {
-	case 400:
-		return NewBadRequestError(errorResp)
-	case 403:
-		return NewForbiddenError(errorResp)
-	case 404:
-		return NewNotFoundError(errorResp)
-	case 422:
-		return NewUnprocessableEntityError(errorResp)
-	default:
-		message := errorResp.Error
-		if message == "" {
-			message = errorResp.Message
-		}
-		if message == "" {
-			message = fmt.Sprintf("HTTP %d error", resp.StatusCode)
-		}
-		return &APIError{
-			statusCode: resp.StatusCode,
-			body:       errorResp,
-			message:    message,
-		}
-	}
+	return core.ParseAPIError(resp)
+}
+
+// ErrorCode returns the detail.status code of the API error in err's chain, or "" if there is none
+func ErrorCode(err error) string {
+	return core.ErrorCode(err)
+}
+
+// IsQuotaExceeded reports whether err was caused by the character quota of the API key being used up
+func IsQuotaExceeded(err error) bool {
+	return core.IsQuotaExceeded(err)
+}
+
+// IsVoiceNotFound reports whether err was caused by an unknown voice ID
+func IsVoiceNotFound(err error) bool {
+	return core.IsVoiceNotFound(err)
+}
+
+// IsTooManyConcurrentRequests reports whether err was caused by exceeding the concurrency limit of the subscription
+func IsTooManyConcurrentRequests(err error) bool {
+	return core.IsTooManyConcurrentRequests(err)
+}
+
+// IsInvalidAPIKey reports whether err was caused by a missing or invalid API key
+func IsInvalidAPIKey(err error) bool {
+	return core.IsInvalidAPIKey(err)
+}
+
+// IsMaxCharacterLimitExceeded reports whether err was caused by submitting too much text in one request
+func IsMaxCharacterLimitExceeded(err error) bool {
+	return core.IsMaxCharacterLimitExceeded(err)
+}
+
+// IsCredentialError reports whether the request may succeed with another API key
+func IsCredentialError(err error) bool {
+	return core.IsCredentialError(err)
+}
+
+// IsRequestError reports whether the request itself must be fixed before it can succeed
+func IsRequestError(err error) bool {
+	return core.IsRequestError(err)
+}
+
+// IsRetryable reports whether the request may succeed if tried again later with the same API key
+func IsRetryable(err error) bool {
+	return core.IsRetryable(err)
 }
@@ -0,0 +1,71 @@
+package elevenlabs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// KeyPool spreads requests over several API keys and fails over when a key is invalid or out of quota
+type KeyPool = core.KeyPool
+
+// KeyStatus describes the state of one key in a KeyPool
+type KeyStatus = core.KeyStatus
+
+// NewKeyPool creates a pool of the given API keys
+func NewKeyPool(apiKeys ...string) *KeyPool {
+	return core.NewKeyPool(apiKeys...)
+}
+
+// WithKeyPool spreads requests over the keys of the pool. Requests rejected with invalid_api_key or
+// quota_exceeded are resent with another key, and each WebSocket session keeps the key it was opened with.
+// The quota of each key is loaded from the subscription endpoint when the key is first used and when the
+// API reports it used up.
+func WithKeyPool(pool *KeyPool) Option {
+	return func(c *Config) {
+		c.Credentials = pool
+	}
+}
+
+// RefreshKeyPool loads the character quota of every key in the client's key pool from the subscription
+// endpoint. Keys rejected by the API are dropped from the pool.
+func (c *Client) RefreshKeyPool(ctx context.Context) error {
+	pool, ok := c.config.Credentials.(*KeyPool)
+	if !ok {
+		return fmt.Errorf("key pool is not configured")
+	}
+
+	var errs []error
+	for _, apiKey := range pool.Keys() {
+		count, limit, resetAt, err := c.fetchQuota(pool)(ctx, apiKey)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pool.SetQuota(apiKey, count, limit, resetAt)
+	}
+	return errors.Join(errs...)
+}
+
+// fetchQuota returns a QuotaFetcher that reads the subscription of a key, dropping keys rejected by the API
+// from the pool
+func (c *Client) fetchQuota(pool *KeyPool) core.QuotaFetcher {
+	return func(ctx context.Context, apiKey string) (int64, int64, time.Time, error) {
+		subscription, err := c.getSubscription(ctx, core.WithAPIKey(apiKey))
+		if err != nil {
+			if core.IsCredentialError(err) && !core.IsQuotaExceeded(err) {
+				pool.Invalidate(apiKey)
+			}
+			return 0, 0, time.Time{}, err
+		}
+
+		var resetAt time.Time
+		if subscription.NextCharacterCountResetUnix > 0 {
+			resetAt = time.Unix(subscription.NextCharacterCountResetUnix, 0)
+		}
+		return subscription.CharacterCount, subscription.CharacterLimit, resetAt, nil
+	}
+}
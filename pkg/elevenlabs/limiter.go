@@ -0,0 +1,50 @@
+package elevenlabs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// ConcurrencyLimiter bounds the number of in-flight requests and WebSocket sessions
+type ConcurrencyLimiter = core.ConcurrencyLimiter
+
+// Priority orders requests waiting for a concurrency slot
+type Priority = core.Priority
+
+// Concurrency priorities
+const (
+	PriorityInteractive = core.PriorityInteractive
+	PriorityDefault     = core.PriorityDefault
+	PriorityBatch       = core.PriorityBatch
+)
+
+// NewConcurrencyLimiter creates a limiter that can be shared by several clients
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	return core.NewConcurrencyLimiter(limit)
+}
+
+// WithPriority sets the concurrency priority of a single request or WebSocket session
+func WithPriority(priority Priority) RequestOption {
+	return core.WithPriority(priority)
+}
+
+// SyncConcurrencyLimit sizes the client's concurrency limiter from the subscription tier of its API key
+// and returns the new limit. Unknown tiers, such as enterprise plans, leave the limit unchanged.
+func (c *Client) SyncConcurrencyLimit(ctx context.Context) (int, error) {
+	limiter := c.httpClient.ConcurrencyLimiter()
+	if limiter == nil {
+		return 0, fmt.Errorf("concurrency limiter is not configured")
+	}
+
+	subscription, err := c.getSubscription(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if limit := core.ConcurrencyLimitForTier(subscription.Tier); limit > 0 {
+		limiter.SetLimit(limit)
+	}
+	return limiter.Limit(), nil
+}
@@ -0,0 +1,19 @@
+package elevenlabs
+
+import "github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+
+// MetricsCollector receives one event per API call and per WebSocket session.
+// See the metrics package for Prometheus and expvar implementations.
+type MetricsCollector = core.MetricsCollector
+
+// RequestMetrics describes a single completed API call
+type RequestMetrics = core.RequestMetrics
+
+// WebSocketMetrics describes a single finished WebSocket session
+type WebSocketMetrics = core.WebSocketMetrics
+
+// HedgeMetrics describes a call sent with a hedging policy
+type HedgeMetrics = core.HedgeMetrics
+
+// HedgeMetricsCollector is implemented by metrics collectors that also record hedged calls
+type HedgeMetricsCollector = core.HedgeMetricsCollector
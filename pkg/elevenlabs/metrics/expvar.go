@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"expvar"
+	"strconv"
+	"strings"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// ExpvarCollector publishes client metrics through the standard expvar package.
+// Values are keyed by "endpoint", "endpoint/status" or "endpoint/voice_id/model_id".
+type ExpvarCollector struct {
+	requests        *expvar.Map
+	errors          *expvar.Map
+	retries         *expvar.Map
+	durationSeconds *expvar.Map
+	ttfbSeconds     *expvar.Map
+	bytesReceived   *expvar.Map
+	characters      *expvar.Map
+
+	wsSessions         *expvar.Map
+	wsErrors           *expvar.Map
+	wsDurationSeconds  *expvar.Map
+	wsMessagesSent     *expvar.Map
+	wsMessagesReceived *expvar.Map
+	wsAudioSeconds     *expvar.Map
+	wsCharacters       *expvar.Map
+
+	hedged         *expvar.Map
+	hedges         *expvar.Map
+	hedgesWon      *expvar.Map
+	hedgeLatencies *expvar.Map
+}
+
+// NewExpvarCollector publishes the metrics under the given expvar name ("elevenlabs" if empty).
+// Collectors created with the same name share the published map.
+func NewExpvarCollector(name string) *ExpvarCollector {
+	if name == "" {
+		name = "elevenlabs"
+	}
+
+	root, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap(name)
+	}
+
+	child := func(key string) *expvar.Map {
+		if m, ok := root.Get(key).(*expvar.Map); ok {
+			return m
+		}
+		m := new(expvar.Map).Init()
+		root.Set(key, m)
+		return m
+	}
+
+	return &ExpvarCollector{
+		requests:           child("requests"),
+		errors:             child("errors"),
+		retries:            child("retries"),
+		durationSeconds:    child("duration_seconds_sum"),
+		ttfbSeconds:        child("time_to_first_byte_seconds_sum"),
+		bytesReceived:      child("bytes_received"),
+		characters:         child("characters_submitted"),
+		wsSessions:         child("websocket_sessions"),
+		wsErrors:           child("websocket_errors"),
+		wsDurationSeconds:  child("websocket_duration_seconds_sum"),
+		wsMessagesSent:     child("websocket_messages_sent"),
+		wsMessagesReceived: child("websocket_messages_received"),
+		wsAudioSeconds:     child("websocket_audio_seconds"),
+		wsCharacters:       child("websocket_characters_submitted"),
+		hedged:             child("hedged_requests"),
+		hedges:             child("hedge_duplicates"),
+		hedgesWon:          child("hedge_wins"),
+		hedgeLatencies:     child("hedged_latency_seconds_sum"),
+	}
+}
+
+// ObserveRequest implements core.MetricsCollector
+func (c *ExpvarCollector) ObserveRequest(m core.RequestMetrics) {
+	status := "error"
+	if m.StatusCode > 0 {
+		status = strconv.Itoa(m.StatusCode)
+	}
+	labelled := key(m.Endpoint, m.VoiceID, m.ModelID)
+
+	c.requests.Add(key(m.Endpoint, status), 1)
+	if m.Err != nil || m.StatusCode >= 400 {
+		c.errors.Add(m.Endpoint, 1)
+	}
+	c.retries.Add(m.Endpoint, int64(m.Retries))
+	c.durationSeconds.AddFloat(labelled, m.Duration.Seconds())
+	c.ttfbSeconds.AddFloat(labelled, m.TimeToFirstByte.Seconds())
+	c.bytesReceived.Add(labelled, m.BytesReceived)
+	c.characters.Add(labelled, int64(m.CharactersSubmitted))
+}
+
+// ObserveWebSocketSession implements core.MetricsCollector
+func (c *ExpvarCollector) ObserveWebSocketSession(m core.WebSocketMetrics) {
+	labelled := key(m.Endpoint, m.VoiceID, m.ModelID)
+
+	c.wsSessions.Add(labelled, 1)
+	if m.Err != nil {
+		c.wsErrors.Add(labelled, 1)
+	}
+	c.wsDurationSeconds.AddFloat(labelled, m.Duration.Seconds())
+	c.wsMessagesSent.Add(m.Endpoint, m.MessagesSent)
+	c.wsMessagesReceived.Add(m.Endpoint, m.MessagesReceived)
+	c.wsAudioSeconds.AddFloat(labelled, m.AudioSeconds)
+	c.wsCharacters.Add(labelled, m.CharactersSubmitted)
+}
+
+// ObserveHedge implements core.HedgeMetricsCollector
+func (c *ExpvarCollector) ObserveHedge(m core.HedgeMetrics) {
+	c.hedged.Add(m.Endpoint, 1)
+	c.hedges.Add(m.Endpoint, int64(m.Hedges))
+	if m.Winner > 0 {
+		c.hedgesWon.Add(m.Endpoint, 1)
+	}
+	c.hedgeLatencies.AddFloat(m.Endpoint, m.Latency.Seconds())
+}
+
+// key joins label values into an expvar map key
+func key(values ...string) string {
+	return strings.Join(values, "/")
+}
@@ -0,0 +1,278 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// DefaultBuckets are the histogram buckets, in seconds, used for latency metrics
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// PrometheusCollector aggregates client metrics and exposes them in the Prometheus text format
+type PrometheusCollector struct {
+	mu      sync.Mutex
+	metrics []*metricVec
+
+	requests     *metricVec
+	retries      *metricVec
+	duration     *metricVec
+	ttfb         *metricVec
+	bytes        *metricVec
+	characters   *metricVec
+	wsSessions   *metricVec
+	wsDuration   *metricVec
+	wsMessages   *metricVec
+	wsAudio      *metricVec
+	wsCharacters *metricVec
+	wsBytes      *metricVec
+	hedged       *metricVec
+	hedges       *metricVec
+	hedgeLatency *metricVec
+	buckets      []float64
+}
+
+// NewPrometheusCollector creates a collector whose metric names start with namespace ("elevenlabs" if empty)
+func NewPrometheusCollector(namespace string) *PrometheusCollector {
+	if namespace == "" {
+		namespace = "elevenlabs"
+	}
+
+	c := &PrometheusCollector{buckets: DefaultBuckets}
+	name := func(s string) string { return namespace + "_" + s }
+
+	c.requests = c.counter(name("requests_total"), "Total number of API calls.", "endpoint", "method", "voice_id", "model_id", "status")
+	c.retries = c.counter(name("request_retries_total"), "Total number of retried API call attempts.", "endpoint")
+	c.duration = c.histogram(name("request_duration_seconds"), "Total duration of API calls including reading the response.", "endpoint", "voice_id", "model_id")
+	c.ttfb = c.histogram(name("request_time_to_first_byte_seconds"), "Time until the first response body byte of API calls.", "endpoint", "voice_id", "model_id")
+	c.bytes = c.counter(name("response_bytes_total"), "Total number of response body bytes received.", "endpoint", "voice_id", "model_id")
+	c.characters = c.counter(name("characters_submitted_total"), "Total number of characters submitted for synthesis.", "endpoint", "voice_id", "model_id")
+	c.wsSessions = c.counter(name("websocket_sessions_total"), "Total number of WebSocket sessions.", "endpoint", "voice_id", "model_id", "result")
+	c.wsDuration = c.histogram(name("websocket_session_duration_seconds"), "Duration of WebSocket sessions.", "endpoint", "voice_id", "model_id")
+	c.wsMessages = c.counter(name("websocket_messages_total"), "Total number of WebSocket messages.", "endpoint", "direction")
+	c.wsBytes = c.counter(name("websocket_received_bytes_total"), "Total number of WebSocket message bytes received.", "endpoint")
+	c.wsAudio = c.counter(name("websocket_audio_seconds_total"), "Total seconds of audio generated over WebSocket sessions.", "endpoint", "voice_id", "model_id")
+	c.wsCharacters = c.counter(name("websocket_characters_submitted_total"), "Total number of characters submitted over WebSocket sessions.", "endpoint", "voice_id", "model_id")
+	c.hedged = c.counter(name("hedged_requests_total"), "Total number of hedged API calls by the request that won.", "endpoint", "winner")
+	c.hedges = c.counter(name("hedge_duplicates_total"), "Total number of duplicate requests sent by hedging.", "endpoint")
+	c.hedgeLatency = c.histogram(name("hedged_request_latency_seconds"), "Time until the winning response of hedged API calls started.", "endpoint")
+
+	return c
+}
+
+// ObserveRequest implements core.MetricsCollector
+func (c *PrometheusCollector) ObserveRequest(m core.RequestMetrics) {
+	status := "error"
+	if m.StatusCode > 0 {
+		status = strconv.Itoa(m.StatusCode)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests.add(1, m.Endpoint, m.Method, m.VoiceID, m.ModelID, status)
+	c.retries.add(float64(m.Retries), m.Endpoint)
+	c.duration.observe(m.Duration.Seconds(), m.Endpoint, m.VoiceID, m.ModelID)
+	if m.Err == nil && m.StatusCode > 0 && m.StatusCode < 400 {
+		c.ttfb.observe(m.TimeToFirstByte.Seconds(), m.Endpoint, m.VoiceID, m.ModelID)
+	}
+	c.bytes.add(float64(m.BytesReceived), m.Endpoint, m.VoiceID, m.ModelID)
+	c.characters.add(float64(m.CharactersSubmitted), m.Endpoint, m.VoiceID, m.ModelID)
+}
+
+// ObserveWebSocketSession implements core.MetricsCollector
+func (c *PrometheusCollector) ObserveWebSocketSession(m core.WebSocketMetrics) {
+	result := "ok"
+	if m.Err != nil {
+		result = "error"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.wsSessions.add(1, m.Endpoint, m.VoiceID, m.ModelID, result)
+	c.wsDuration.observe(m.Duration.Seconds(), m.Endpoint, m.VoiceID, m.ModelID)
+	c.wsMessages.add(float64(m.MessagesSent), m.Endpoint, "sent")
+	c.wsMessages.add(float64(m.MessagesReceived), m.Endpoint, "received")
+	c.wsBytes.add(float64(m.BytesReceived), m.Endpoint)
+	c.wsAudio.add(m.AudioSeconds, m.Endpoint, m.VoiceID, m.ModelID)
+	c.wsCharacters.add(float64(m.CharactersSubmitted), m.Endpoint, m.VoiceID, m.ModelID)
+}
+
+// ObserveHedge implements core.HedgeMetricsCollector
+func (c *PrometheusCollector) ObserveHedge(m core.HedgeMetrics) {
+	winner := "none"
+	switch {
+	case m.Winner == 0:
+		winner = "original"
+	case m.Winner > 0:
+		winner = "hedge"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hedged.add(1, m.Endpoint, winner)
+	c.hedges.add(float64(m.Hedges), m.Endpoint)
+	if m.Winner >= 0 {
+		c.hedgeLatency.observe(m.Latency.Seconds(), m.Endpoint)
+	}
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, m := range c.metrics {
+		m.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP serves the metrics so the collector can be mounted as a scrape endpoint
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// counter registers a new counter metric
+func (c *PrometheusCollector) counter(name, help string, labels ...string) *metricVec {
+	m := &metricVec{name: name, help: help, kind: "counter", labels: labels, series: make(map[string]*series)}
+	c.metrics = append(c.metrics, m)
+	return m
+}
+
+// histogram registers a new histogram metric
+func (c *PrometheusCollector) histogram(name, help string, labels ...string) *metricVec {
+	m := &metricVec{name: name, help: help, kind: "histogram", labels: labels, buckets: c.buckets, series: make(map[string]*series)}
+	c.metrics = append(c.metrics, m)
+	return m
+}
+
+// metricVec is a metric family partitioned by label values
+type metricVec struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series holds the state of one labelled time series
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+// get returns the series for the given label values, creating it if needed
+func (m *metricVec) get(labelValues []string) *series {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues, counts: make([]uint64, len(m.buckets))}
+		m.series[key] = s
+	}
+	return s
+}
+
+// add increments a counter
+func (m *metricVec) add(v float64, labelValues ...string) {
+	m.get(labelValues).value += v
+}
+
+// observe records a histogram sample
+func (m *metricVec) observe(v float64, labelValues ...string) {
+	s := m.get(labelValues)
+	for i, bound := range m.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.value += v
+}
+
+// write writes the metric family in the text exposition format
+func (m *metricVec) write(w *bufio.Writer) {
+	if len(m.series) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := m.series[key]
+		if m.kind == "counter" {
+			fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+			continue
+		}
+
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", formatValue(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), s.count)
+	}
+}
+
+// formatLabels renders a label set, optionally with one extra label
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+labelEscaper.Replace(extraValue)+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes label values as required by the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatValue renders a sample value
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,272 @@
+package elevenlabs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	EnvAPIKey      = "ELEVENLABS_API_KEY"
+	EnvBaseURL     = "ELEVENLABS_BASE_URL"
+	EnvEnvironment = "ELEVENLABS_ENVIRONMENT"
+	EnvTimeout     = "ELEVENLABS_TIMEOUT"
+	EnvMaxRetries  = "ELEVENLABS_MAX_RETRIES"
+	EnvProxy       = "ELEVENLABS_PROXY"
+	EnvVoiceID     = "ELEVENLABS_VOICE_ID"
+	EnvModelID     = "ELEVENLABS_MODEL_ID"
+	EnvProfile     = "ELEVENLABS_PROFILE"
+	EnvConfigFile  = "ELEVENLABS_CONFIG_FILE"
+)
+
+// DefaultProfileName is the profile used when neither ELEVENLABS_PROFILE nor default_profile names one
+const DefaultProfileName = "default"
+
+// Profile is a named set of client settings, read from the config file or the environment.
+// Empty fields leave the default configuration unchanged.
+type Profile struct {
+	APIKey string `json:"api_key,omitempty"`
+	// Environment is "production", "us" or "eu"
+	Environment  string `json:"environment,omitempty"`
+	BaseURL      string `json:"base_url,omitempty"`
+	WebSocketURL string `json:"websocket_url,omitempty"`
+	// Timeout is a duration such as "90s", or a number of seconds
+	Timeout    string `json:"timeout,omitempty"`
+	MaxRetries *int   `json:"max_retries,omitempty"`
+	Proxy      string `json:"proxy,omitempty"`
+	VoiceID    string `json:"voice_id,omitempty"`
+	ModelID    string `json:"model_id,omitempty"`
+}
+
+// ConfigFile is the layout of the config file holding the profiles
+type ConfigFile struct {
+	DefaultProfile string             `json:"default_profile,omitempty"`
+	Profiles       map[string]Profile `json:"profiles"`
+}
+
+// NewClientFromEnv creates a client from the profile named by ELEVENLABS_PROFILE and the ELEVENLABS_*
+// environment variables. Environment variables override the profile, and opts override both.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	return NewClientFromProfile(os.Getenv(EnvProfile), opts...)
+}
+
+// NewClientFromProfile creates a client from the named profile of the config file and the ELEVENLABS_*
+// environment variables. An empty name selects the default profile, if the file has one.
+// Environment variables override the profile, and opts override both.
+func NewClientFromProfile(name string, opts ...Option) (*Client, error) {
+	profile, err := LoadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	envProfile, err := ProfileFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	profile = profile.merge(envProfile)
+
+	config := DefaultConfig()
+	if err := profile.apply(&config); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return NewClientWithConfig(config)
+}
+
+// ConfigFilePath returns the path of the config file: ELEVENLABS_CONFIG_FILE if set,
+// otherwise elevenlabs/config.json in the user config directory
+func ConfigFilePath() (string, error) {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "elevenlabs", "config.json"), nil
+}
+
+// LoadConfigFile reads the config file. A missing file yields an empty ConfigFile.
+func LoadConfigFile() (*ConfigFile, error) {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ConfigFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file ConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// LoadProfile returns the named profile of the config file. An empty name selects default_profile,
+// or the profile named "default"; if there is none, an empty profile is returned.
+// Naming a profile that does not exist is an error.
+func LoadProfile(name string) (Profile, error) {
+	file, err := LoadConfigFile()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	explicit := name != ""
+	if !explicit {
+		name = file.DefaultProfile
+		explicit = name != ""
+	}
+	if !explicit {
+		name = DefaultProfileName
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok && explicit {
+		return Profile{}, fmt.Errorf("profile %q not found in config file", name)
+	}
+	return profile, nil
+}
+
+// ProfileFromEnv reads a profile from the ELEVENLABS_* environment variables
+func ProfileFromEnv() (Profile, error) {
+	profile := Profile{
+		APIKey:      strings.TrimSpace(os.Getenv(EnvAPIKey)),
+		Environment: os.Getenv(EnvEnvironment),
+		BaseURL:     os.Getenv(EnvBaseURL),
+		Timeout:     os.Getenv(EnvTimeout),
+		Proxy:       os.Getenv(EnvProxy),
+		VoiceID:     os.Getenv(EnvVoiceID),
+		ModelID:     os.Getenv(EnvModelID),
+	}
+	if value := os.Getenv(EnvMaxRetries); value != "" {
+		maxRetries, err := strconv.Atoi(value)
+		if err != nil {
+			return Profile{}, fmt.Errorf("invalid %s %q", EnvMaxRetries, value)
+		}
+		profile.MaxRetries = &maxRetries
+	}
+	return profile, nil
+}
+
+// merge returns the profile with every field set in override replaced
+func (p Profile) merge(override Profile) Profile {
+	setString := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+
+	setString(&p.APIKey, override.APIKey)
+	if override.Environment != "" || override.BaseURL != "" {
+		// The environment and base URL of the profile describe one deployment, so they are replaced together
+		p.Environment = override.Environment
+		p.BaseURL = override.BaseURL
+		p.WebSocketURL = override.WebSocketURL
+	}
+	setString(&p.Timeout, override.Timeout)
+	setString(&p.Proxy, override.Proxy)
+	setString(&p.VoiceID, override.VoiceID)
+	setString(&p.ModelID, override.ModelID)
+	if override.MaxRetries != nil {
+		p.MaxRetries = override.MaxRetries
+	}
+	return p
+}
+
+// apply copies the settings of the profile into config
+func (p Profile) apply(config *Config) error {
+	if p.APIKey != "" {
+		config.APIKey = p.APIKey
+	}
+
+	if p.Environment != "" {
+		env, err := environmentByName(p.Environment)
+		if err != nil {
+			return err
+		}
+		config.Environment = env
+	}
+	if p.BaseURL != "" {
+		config.Environment.BaseURL = strings.TrimRight(p.BaseURL, "/")
+		// Derived from the base URL unless set explicitly
+		config.Environment.WebSocketURL = p.WebSocketURL
+		if p.Environment == "" {
+			config.Environment.Region = ""
+		}
+	}
+
+	if p.Timeout != "" {
+		timeout, err := parseTimeout(p.Timeout)
+		if err != nil {
+			return err
+		}
+		config.Timeout = timeout
+	}
+
+	if p.MaxRetries != nil {
+		if *p.MaxRetries < 0 {
+			return fmt.Errorf("invalid max retries %d", *p.MaxRetries)
+		}
+		config.RetryConfig.MaxAttempts = *p.MaxRetries
+	}
+
+	if p.Proxy != "" {
+		proxyURL, err := url.Parse(p.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", p.Proxy)
+		}
+		config.Transport.Proxy = proxyURL
+	}
+
+	if p.VoiceID != "" {
+		config.DefaultVoiceID = p.VoiceID
+	}
+	if p.ModelID != "" {
+		config.DefaultModelID = p.ModelID
+	}
+	return nil
+}
+
+// environmentByName returns the predefined environment with the given name
+func environmentByName(name string) (core.Environment, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "production", "prod", core.RegionGlobal:
+		return ProductionEnv, nil
+	case core.RegionUS:
+		return ProductionUSEnv, nil
+	case core.RegionEU:
+		return ProductionEUEnv, nil
+	default:
+		return core.Environment{}, fmt.Errorf("unknown environment %q: expected production, us or eu", name)
+	}
+}
+
+// parseTimeout parses a duration such as "90s", or a plain number of seconds
+func parseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,57 @@
+package elevenlabs
+
+import (
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// RequestOption configures a single API call and can be passed to any service method
+type RequestOption = core.RequestOption
+
+// WithRequestTimeout sets the timeout for a single request
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return core.WithRequestTimeout(timeout)
+}
+
+// WithMaxRetries overrides the maximum number of retries for a single request
+func WithMaxRetries(maxRetries int) RequestOption {
+	return core.WithMaxRetries(maxRetries)
+}
+
+// WithRequestAPIKey overrides the API key for a single request
+func WithRequestAPIKey(apiKey string) RequestOption {
+	return core.WithAPIKey(apiKey)
+}
+
+// WithRequestHeader adds an extra header to a single request
+func WithRequestHeader(key, value string) RequestOption {
+	return core.WithHeader(key, value)
+}
+
+// WithRequestQueryParam adds an extra query parameter to a single request
+func WithRequestQueryParam(key, value string) RequestOption {
+	return core.WithQueryParam(key, value)
+}
+
+// WithChunkSize sets the chunk size used when streaming a response body
+func WithChunkSize(size int) RequestOption {
+	return core.WithChunkSize(size)
+}
+
+// HedgePolicy sends duplicate requests when a response is slow to start and uses the first response
+type HedgePolicy = core.HedgePolicy
+
+// HedgeTarget is where a duplicate request is sent, such as another region or API key
+type HedgeTarget = core.HedgeTarget
+
+// WithRequestHedging sends a single request with the given hedging policy, overriding WithTTSHedging
+func WithRequestHedging(policy HedgePolicy) RequestOption {
+	return core.WithHedging(policy)
+}
+
+// WithoutCache sends a single text-to-speech request to the API even if the cache holds its response,
+// and does not cache the result
+func WithoutCache() RequestOption {
+	return core.WithoutCache()
+}
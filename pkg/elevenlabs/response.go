@@ -0,0 +1,7 @@
+package elevenlabs
+
+import "github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+
+// ResponseMetadata holds the status, headers, request ID and character cost of an API response.
+// Service clients return it through their WithRawResponse field.
+type ResponseMetadata = core.ResponseMetadata
@@ -0,0 +1,35 @@
+package elevenlabs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// subscription is the part of the user subscription used to size limits and track quotas
+type subscription struct {
+	Tier                        string `json:"tier"`
+	CharacterCount              int64  `json:"character_count"`
+	CharacterLimit              int64  `json:"character_limit"`
+	NextCharacterCountResetUnix int64  `json:"next_character_count_reset_unix"`
+}
+
+// getSubscription fetches the subscription of the client's API key, or of the key set in opts
+func (c *Client) getSubscription(ctx context.Context, opts ...RequestOption) (*subscription, error) {
+	resp, err := c.httpClient.Request(ctx, http.MethodGet, "v1/user/subscription", nil, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, ParseAPIError(resp)
+	}
+
+	var result subscription
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode subscription: %w", err)
+	}
+	return &result, nil
+}
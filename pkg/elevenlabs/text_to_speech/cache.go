@@ -0,0 +1,324 @@
+package text_to_speech
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// Kinds of cached responses. Convert and Stream return the same audio, so they share entries.
+const (
+	cacheKindAudio      = "audio"
+	cacheKindTimestamps = "timestamps"
+)
+
+// audioCache serves responses from the client's cache and shares one API call between concurrent
+// identical requests
+type audioCache struct {
+	httpClient *core.HTTPClient
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// newAudioCache creates the cache layer of a client
+func newAudioCache(httpClient *core.HTTPClient) *audioCache {
+	return &audioCache{
+		httpClient: httpClient,
+		flights:    make(map[string]*flight),
+	}
+}
+
+// cacheKey hashes everything in a request that determines the generated audio and who may receive it: the voice,
+// the query parameters such as the output format, the JSON body with the text, model, voice settings, seed and
+// pronunciation dictionary locators, and the per-request API key and headers
+func cacheKey(kind, voiceID string, options core.RequestOptions, body []byte) string {
+	query := make(url.Values, len(options.AdditionalQuery))
+	for key, values := range options.AdditionalQuery {
+		query[key] = values
+	}
+	// Logging does not change the audio
+	query.Del("enable_logging")
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", kind, voiceID, query.Encode())
+
+	// Audio requested with another key belongs to another account, which is billed for it and may have other voices
+	fmt.Fprintf(h, "%s\n", options.APIKey)
+	names := make([]string, 0, len(options.AdditionalHeaders))
+	for name := range options.AdditionalHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "%s: %s\n", http.CanonicalHeaderKey(name), options.AdditionalHeaders[name])
+	}
+
+	h.Write(body)
+	return kind + "-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns a flight producing the response to a request, or nil if the client has no cache or opts skip it.
+// kind, the request body and opts determine the cache key; see fetch.
+func (a *audioCache) lookup(ctx context.Context, endpoint, kind, voiceID string, body []byte, chunkSize int, opts []core.RequestOption, send func(context.Context) (*http.Response, error)) *flight {
+	options := core.NewRequestOptions(opts...)
+	if cache, _ := a.httpClient.TTSCache(); cache == nil || options.SkipCache {
+		return nil
+	}
+	return a.fetch(ctx, endpoint, cacheKey(kind, voiceID, options, body), voiceID, chunkSize, send)
+}
+
+// fetch returns a flight producing the response for key. It is replayed from the cache if present, joined if an
+// identical request is in progress, and otherwise sent with send; the key covers every option send depends on. endpoint keeps flights of different endpoints
+// apart. The caller must release the flight.
+func (a *audioCache) fetch(ctx context.Context, endpoint, key, voiceID string, chunkSize int, send func(context.Context) (*http.Response, error)) *flight {
+	cache, ttl := a.httpClient.TTSCache()
+
+	if entry, ok, err := cache.Get(ctx, key); err == nil && ok {
+		return replay(entry, chunkSize)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	flightKey := endpoint + "/" + key
+	if f, ok := a.flights[flightKey]; ok {
+		f.refs++
+		return f
+	}
+
+	// The call outlives the request that started it as long as other requests wait for it
+	flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	f := newFlight()
+	f.refs = 1
+	f.release = func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		f.refs--
+		if f.refs == 0 && a.flights[flightKey] == f {
+			delete(a.flights, flightKey)
+			cancel()
+		}
+	}
+	a.flights[flightKey] = f
+
+	go func() {
+		defer cancel()
+		if err := f.run(flightCtx, send, chunkSize); err == nil {
+			// A failure to cache leaves the response uncached
+			cache.Set(flightCtx, key, f.entry(voiceID), ttl)
+		}
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.flights[flightKey] == f {
+			delete(a.flights, flightKey)
+		}
+	}()
+	return f
+}
+
+// flight is a response that one or more requests read as it arrives
+type flight struct {
+	mu       sync.Mutex
+	changed  chan struct{}
+	metadata core.ResponseMetadata
+	header   http.Header
+	chunks   [][]byte
+	started  bool
+	done     bool
+	err      error
+
+	// refs counts the requests reading the flight, guarded by the mutex of the audio cache
+	refs    int
+	release func()
+}
+
+// newFlight creates a flight waiting for its response
+func newFlight() *flight {
+	return &flight{
+		changed: make(chan struct{}),
+		release: func() {},
+	}
+}
+
+// replay creates a finished flight from a cache entry, split into chunks as a stream would be
+func replay(entry *core.CacheEntry, chunkSize int) *flight {
+	// Readers get their own copy, so they cannot change the cached audio or headers
+	entry = entry.Clone()
+	f := newFlight()
+	f.header = entry.Header
+	f.metadata = core.NewResponseMetadata(&http.Response{StatusCode: http.StatusOK, Header: entry.Header})
+	f.metadata.Cached = true
+	f.metadata.CharacterCost = 0
+	for data := entry.Data; len(data) > 0; {
+		n := chunkSize
+		if n <= 0 || n > len(data) {
+			n = len(data)
+		}
+		f.chunks = append(f.chunks, data[:n])
+		data = data[n:]
+	}
+	f.started = true
+	f.done = true
+	return f
+}
+
+// run sends the request and records its response body chunk by chunk
+func (f *flight) run(ctx context.Context, send func(context.Context) (*http.Response, error), chunkSize int) error {
+	resp, err := send(ctx)
+	if err != nil {
+		f.finish(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	f.update(func() {
+		f.metadata = core.NewResponseMetadata(resp)
+		f.header = resp.Header.Clone()
+		f.started = true
+	})
+
+	if chunkSize <= 0 {
+		chunkSize = 8192
+	}
+	for {
+		buffer := make([]byte, chunkSize)
+		n, err := resp.Body.Read(buffer)
+		if n > 0 {
+			f.update(func() {
+				f.chunks = append(f.chunks, buffer[:n])
+			})
+		}
+		if err == io.EOF {
+			f.finish(nil)
+			return nil
+		}
+		if err != nil {
+			f.finish(err)
+			return err
+		}
+	}
+}
+
+// update changes the flight and wakes the requests waiting for it
+func (f *flight) update(change func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	change()
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// finish ends the flight with err, or successfully if err is nil
+func (f *flight) finish(err error) {
+	f.update(func() {
+		f.done = true
+		f.err = err
+	})
+}
+
+// wait blocks until ready reports true, the flight fails or ctx is done
+func (f *flight) wait(ctx context.Context, ready func() bool) error {
+	for {
+		f.mu.Lock()
+		if ready() {
+			f.mu.Unlock()
+			return nil
+		}
+		if f.done {
+			err := f.err
+			f.mu.Unlock()
+			return err
+		}
+		changed := f.changed
+		f.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// response waits for the response headers and returns the response metadata
+func (f *flight) response(ctx context.Context) (core.ResponseMetadata, error) {
+	if err := f.wait(ctx, func() bool { return f.started }); err != nil {
+		return core.ResponseMetadata{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.metadata, nil
+}
+
+// body waits for the whole response body and returns it with the response metadata
+func (f *flight) body(ctx context.Context) (core.ResponseMetadata, []byte, error) {
+	if err := f.wait(ctx, func() bool { return f.done && f.err == nil }); err != nil {
+		return core.ResponseMetadata{}, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.metadata, bytes.Join(f.chunks, nil), nil
+}
+
+// stream sends the chunks of the response body from the start, including those received before the call,
+// and closes the channel when the body ends, the flight fails or ctx is done. The returned function reports the
+// error that ended the stream early. The flight is released afterwards.
+func (f *flight) stream(ctx context.Context) (<-chan []byte, func() error) {
+	ch := make(chan []byte)
+	result := &core.StreamResult{}
+
+	go func() {
+		defer close(ch)
+		defer f.release()
+
+		for next := 0; ; next++ {
+			if err := f.wait(ctx, func() bool { return next < len(f.chunks) }); err != nil {
+				result.Set(err)
+				return
+			}
+			f.mu.Lock()
+			if next >= len(f.chunks) {
+				// The flight finished successfully with every chunk sent
+				f.mu.Unlock()
+				return
+			}
+			chunk := f.chunks[next]
+			f.mu.Unlock()
+
+			select {
+			case ch <- chunk:
+			case <-ctx.Done():
+				result.Set(ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return ch, result.Err
+}
+
+// entry returns the cache entry for the finished flight
+func (f *flight) entry(voiceID string) *core.CacheEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	header := f.header.Clone()
+	// The cost was billed to the request that generated the audio
+	header.Del(core.CharacterCostHeader)
+	return &core.CacheEntry{
+		VoiceID: voiceID,
+		Data:    bytes.Join(f.chunks, nil),
+		Header:  header,
+	}
+}
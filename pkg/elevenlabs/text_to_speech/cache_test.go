@@ -0,0 +1,111 @@
+package text_to_speech
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/cache"
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+func TestConcurrentIdenticalRequestsShareOneCall(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte("audio"))
+	}, core.Config{TTSCache: cache.NewMemoryCache(0)})
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			audio, err := client.Convert(context.Background(), ConvertRequest{VoiceID: "voice", Text: "hello"})
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = audio
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected one API call, got %d", got)
+	}
+	for _, audio := range results {
+		if string(audio) != "audio" {
+			t.Errorf("unexpected audio %q", audio)
+		}
+	}
+}
+
+func TestCachedAudioIsReplayedAsCopy(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set(core.CharacterCostHeader, "5")
+		w.Write([]byte("audio"))
+	}, core.Config{TTSCache: cache.NewMemoryCache(0)})
+	req := ConvertRequest{VoiceID: "voice", Text: "hello"}
+
+	first, err := client.WithRawResponse.Convert(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached || first.CharacterCost != 5 {
+		t.Errorf("expected the first response to come from the API, got %+v", first.ResponseMetadata)
+	}
+
+	// Callers cannot change what the next request is served
+	cached, err := client.WithRawResponse.Convert(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached.Data[0] = 'X'
+	cached.Header.Set("Content-Type", "changed")
+
+	again, err := client.WithRawResponse.Stream(context.Background(), StreamRequest{VoiceID: "voice", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audio bytes.Buffer
+	for chunk := range again.Data {
+		audio.Write(chunk)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected one API call, got %d", got)
+	}
+	if !again.Cached || again.CharacterCost != 0 || audio.String() != "audio" || again.ContentType != "audio/mpeg" {
+		t.Errorf("expected the unchanged audio from the cache, got %q with %+v", audio.String(), again.ResponseMetadata)
+	}
+}
+
+func TestWithoutCacheSendsRequest(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte("audio"))
+	}, core.Config{TTSCache: cache.NewMemoryCache(0)})
+	req := ConvertRequest{VoiceID: "voice", Text: "hello"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Convert(context.Background(), req, core.WithoutCache()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected every request to reach the API, got %d calls", got)
+	}
+}
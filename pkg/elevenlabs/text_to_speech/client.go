@@ -1,11 +1,10 @@
 package text_to_speech
 
 import (
-	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
 )
@@ -13,203 +12,87 @@ import (
 // Client handles text-to-speech operations
 type Client struct {
 	httpClient *core.HTTPClient
+
+	// WithRawResponse performs the same HTTP operations and also returns the response metadata
+	WithRawResponse *RawClient
 }
 
 // NewClient creates a new text-to-speech client
 func NewClient(httpClient *core.HTTPClient) *Client {
 	return &Client{
-		httpClient: httpClient,
+		httpClient:      httpClient,
+		WithRawResponse: NewRawClient(httpClient),
 	}
 }
 
 // Convert converts text to speech and returns audio bytes
-func (c *Client) Convert(ctx context.Context, req ConvertRequest) ([]byte, error) {
-	// Build the request path
-	path := fmt.Sprintf("v1/text-to-speech/%s", req.VoiceID)
-
-	// Prepare request body
-	requestBody, err := json.Marshal(req)
+func (c *Client) Convert(ctx context.Context, req ConvertRequest, opts ...core.RequestOption) ([]byte, error) {
+	resp, err := c.WithRawResponse.Convert(ctx, req, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Set headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"Accept":       "audio/mpeg",
-	}
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "POST", path, bytes.NewReader(requestBody), headers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
+		return nil, err
 	}
-
-	// Read the audio data
-	audioData := make([]byte, 0)
-	buffer := make([]byte, 8192)
-
-	for {
-		n, err := resp.Body.Read(buffer)
-		if n > 0 {
-			audioData = append(audioData, buffer[:n]...)
-		}
-		if err != nil {
-			break
-		}
-	}
-
-	return audioData, nil
+	return resp.Data, nil
 }
 
 // ConvertWithTimestamps converts text to speech with timing information
-func (c *Client) ConvertWithTimestamps(ctx context.Context, req ConvertRequest) (*TimestampResponse, error) {
-	// Build the request path
-	path := fmt.Sprintf("v1/text-to-speech/%s/with-timestamps", req.VoiceID)
-
-	// Prepare request body
-	requestBody, err := json.Marshal(req)
+func (c *Client) ConvertWithTimestamps(ctx context.Context, req ConvertRequest, opts ...core.RequestOption) (*TimestampResponse, error) {
+	resp, err := c.WithRawResponse.ConvertWithTimestamps(ctx, req, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
-
-	// Set headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"Accept":       "application/json",
-	}
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "POST", path, bytes.NewReader(requestBody), headers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
-	}
-
-	// Parse the response
-	var result TimestampResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &result, nil
+	return resp.Data, nil
 }
 
-// Stream converts text to speech and returns a channel of audio chunks
-func (c *Client) Stream(ctx context.Context, req StreamRequest) (<-chan []byte, error) {
-	// Build the request path
-	path := fmt.Sprintf("v1/text-to-speech/%s/stream", req.VoiceID)
-
-	// Prepare request body
-	requestBody, err := json.Marshal(req)
+// Stream converts text to speech and returns a channel of audio chunks. The channel is also closed when the
+// stream fails; WithRawResponse.Stream reports the error.
+func (c *Client) Stream(ctx context.Context, req StreamRequest, opts ...core.RequestOption) (<-chan []byte, error) {
+	resp, err := c.WithRawResponse.Stream(ctx, req, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Set headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"Accept":       "audio/mpeg",
+		return nil, err
 	}
+	return resp.Data, nil
+}
 
-	// Make the streaming request
-	resp, err := c.httpClient.Stream(ctx, "POST", path, bytes.NewReader(requestBody), headers)
+// StreamWithTimestamps converts text to speech with timing information in streaming mode. The channel is also
+// closed when the stream fails; WithRawResponse.StreamWithTimestamps reports the error.
+func (c *Client) StreamWithTimestamps(ctx context.Context, req StreamRequest, opts ...core.RequestOption) (<-chan TimestampChunk, error) {
+	resp, err := c.WithRawResponse.StreamWithTimestamps(ctx, req, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		resp.Body.Close()
-		return nil, parseAPIError(resp)
+		return nil, err
 	}
-
-	// Return streaming channel
-	return core.StreamResponse(resp, 8192), nil
+	return resp.Data, nil
 }
 
-// StreamWithTimestamps converts text to speech with timing information in streaming mode
-func (c *Client) StreamWithTimestamps(ctx context.Context, req StreamRequest) (<-chan TimestampChunk, error) {
-	// Build the request path
-	path := fmt.Sprintf("v1/text-to-speech/%s/stream-with-timestamps", req.VoiceID)
+// ConvertRealtime performs real-time text-to-speech conversion via WebSocket. The channel carries the decoded
+// audio of each frame and is closed when the session ends; req.Errors reports an early end.
+func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest, opts ...core.RequestOption) (<-chan []byte, error) {
+	req.VoiceID, req.ModelID = withDefaults(c.httpClient, req.VoiceID, req.ModelID)
+	opts = append([]core.RequestOption{operation("text_to_speech.convert_realtime", req.VoiceID, req.ModelID, "")}, opts...)
+	options := core.NewRequestOptions(opts...)
 
-	// Prepare request body
-	requestBody, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
+	// Create WebSocket client
+	wsClient := c.httpClient.NewWebSocketClient(opts...)
 
-	// Set headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"Accept":       "application/json",
+	// Build the WebSocket endpoint path; the model and output format are read from the query string
+	path, err := core.ExpandPath("v1/text-to-speech/{voice_id}/stream-input", req)
+	if err != nil {
+		return nil, err
 	}
-
-	// Make the streaming request
-	resp, err := c.httpClient.Stream(ctx, "POST", path, bytes.NewReader(requestBody), headers)
+	query, err := core.EncodeQuery(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		resp.Body.Close()
-		return nil, parseAPIError(resp)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
 	}
 
-	// Create channel for timestamp chunks
-	ch := make(chan TimestampChunk)
-
-	go func() {
-		defer close(ch)
-		defer resp.Body.Close()
-
-		// Stream JSON lines
-		lines := core.StreamLines(ctx, resp)
-		for chunk := range lines {
-			if chunk.Err != nil {
-				return
-			}
-
-			// Parse each line as a TimestampChunk
-			var timestampChunk TimestampChunk
-			if err := json.Unmarshal(chunk.Data, &timestampChunk); err == nil {
-				ch <- timestampChunk
-			}
-		}
-	}()
-
-	return ch, nil
-}
-
-// ConvertRealtime performs real-time text-to-speech conversion via WebSocket
-func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest) (<-chan []byte, error) {
-	// Create WebSocket client
-	wsClient := core.NewWebSocketClient(c.httpClient.GetWebSocketURL(), c.httpClient.GetAPIKey())
-
-	// Build the WebSocket endpoint path
-	path := fmt.Sprintf("v1/text-to-speech/%s/stream-input", req.VoiceID)
-
 	// Connect to WebSocket
-	if err := wsClient.Connect(ctx, path, nil); err != nil {
+	if err := wsClient.Connect(ctx, path, options.AdditionalHeaders); err != nil {
 		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
 	// Send initial configuration
 	initMessage := map[string]interface{}{
-		"model_id":       req.ModelID,
-		"output_format":  req.OutputFormat,
 		"voice_settings": req.VoiceSettings,
 	}
 
@@ -221,6 +104,11 @@ func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest) (<-ch
 	// Create audio output channel
 	audioCh := make(chan []byte)
 
+	outputFormat := OutputFormatMP3_44100_128
+	if req.OutputFormat != nil {
+		outputFormat = *req.OutputFormat
+	}
+
 	// Start goroutines for sending text and receiving audio
 	go func() {
 		defer wsClient.Close()
@@ -233,6 +121,7 @@ func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest) (<-ch
 			if err := wsClient.Send(textMessage); err != nil {
 				return
 			}
+			wsClient.RecordCharacters(len([]rune(text)))
 		}
 
 		// Send end of stream
@@ -259,9 +148,15 @@ func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest) (<-ch
 				// Parse the message to extract audio data
 				var message map[string]interface{}
 				if err := json.Unmarshal(data, &message); err == nil {
-					if audioData, ok := message["audio"].(string); ok {
-						// Audio is base64 encoded, you might want to decode it
-						audioCh <- []byte(audioData)
+					if encoded, ok := message["audio"].(string); ok && encoded != "" {
+						// Audio is base64 encoded; a frame that cannot be decoded ends the session
+						audioData, err := base64.StdEncoding.DecodeString(encoded)
+						if err != nil {
+							reportError(req.Errors, fmt.Errorf("failed to decode audio frame: %w", err))
+							return
+						}
+						wsClient.RecordAudio(outputFormat.AudioDuration(len(audioData)))
+						audioCh <- audioData
 					}
 				}
 			}
@@ -271,9 +166,35 @@ func (c *Client) ConvertRealtime(ctx context.Context, req RealtimeRequest) (<-ch
 	return audioCh, nil
 }
 
-// parseAPIError parses an HTTP error response
-func parseAPIError(resp *http.Response) error {
-	// This is a simplified error parser - you might want to implement
-	// more sophisticated error parsing based on the actual API responses
-	return fmt.Errorf("API error: %s", resp.Status)
+// reportError sends the error that ended a real-time session to errs, if it is set and has room for it
+func reportError(errs chan<- error, err error) {
+	if errs == nil {
+		return
+	}
+	select {
+	case errs <- err:
+	default:
+	}
+}
+
+// InvalidateVoice removes the cached audio generated with a voice, for example after its settings change
+func (c *Client) InvalidateVoice(ctx context.Context, voiceID string) error {
+	cache, _ := c.httpClient.TTSCache()
+	if cache == nil {
+		return nil
+	}
+	return cache.InvalidateVoice(ctx, voiceID)
+}
+
+// operation labels a request with the endpoint, voice, model and submitted characters
+func operation(endpoint, voiceID string, modelID *string, text string) core.RequestOption {
+	op := core.Operation{
+		Endpoint:   endpoint,
+		VoiceID:    voiceID,
+		Characters: len([]rune(text)),
+	}
+	if modelID != nil {
+		op.ModelID = *modelID
+	}
+	return core.WithOperation(op)
 }
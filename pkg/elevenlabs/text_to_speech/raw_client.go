@@ -0,0 +1,286 @@
+package text_to_speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// RawClient performs text-to-speech operations and returns the response metadata alongside each result
+type RawClient struct {
+	httpClient *core.HTTPClient
+	cache      *audioCache
+}
+
+// NewRawClient creates a new raw text-to-speech client
+func NewRawClient(httpClient *core.HTTPClient) *RawClient {
+	return &RawClient{
+		httpClient: httpClient,
+		cache:      newAudioCache(httpClient),
+	}
+}
+
+// Convert converts text to speech and returns audio bytes with the response metadata
+func (c *RawClient) Convert(ctx context.Context, req ConvertRequest, opts ...core.RequestOption) (*core.RawResponse[[]byte], error) {
+	req.VoiceID, req.ModelID = withDefaults(c.httpClient, req.VoiceID, req.ModelID)
+	opts = append([]core.RequestOption{operation("text_to_speech.convert", req.VoiceID, req.ModelID, req.Text), hedging(c.httpClient)}, opts...)
+
+	// Build the request path and query string
+	path, query, err := core.RequestTarget("v1/text-to-speech/{voice_id}", req)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, query)
+
+	// Prepare request body
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "audio/mpeg",
+	}
+
+	// Serve repeated requests from the cache
+	f := c.cache.lookup(ctx, "convert", cacheKindAudio, req.VoiceID, requestBody, 0, opts, func(ctx context.Context) (*http.Response, error) {
+		return send(ctx, c.httpClient.Request, path, requestBody, headers, opts)
+	})
+	if f != nil {
+		defer f.release()
+		metadata, audioData, err := f.body(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &core.RawResponse[[]byte]{ResponseMetadata: metadata, Data: audioData}, nil
+	}
+
+	// Make the request
+	resp, err := send(ctx, c.httpClient.Request, path, requestBody, headers, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Read the audio data; a body cut short by a timeout or a reset connection is an error, not shorter audio
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+
+	return core.NewRawResponse(resp, audioData), nil
+}
+
+// ConvertWithTimestamps converts text to speech with timing information and the response metadata
+func (c *RawClient) ConvertWithTimestamps(ctx context.Context, req ConvertRequest, opts ...core.RequestOption) (*core.RawResponse[*TimestampResponse], error) {
+	req.VoiceID, req.ModelID = withDefaults(c.httpClient, req.VoiceID, req.ModelID)
+	opts = append([]core.RequestOption{operation("text_to_speech.convert_with_timestamps", req.VoiceID, req.ModelID, req.Text)}, opts...)
+
+	// Build the request path and query string
+	path, query, err := core.RequestTarget("v1/text-to-speech/{voice_id}/with-timestamps", req)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, query)
+
+	// Prepare request body
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	// Serve repeated requests from the cache
+	f := c.cache.lookup(ctx, "convert_with_timestamps", cacheKindTimestamps, req.VoiceID, requestBody, 0, opts, func(ctx context.Context) (*http.Response, error) {
+		return send(ctx, c.httpClient.Request, path, requestBody, headers, opts)
+	})
+	if f != nil {
+		defer f.release()
+		metadata, data, err := f.body(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var result TimestampResponse
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return &core.RawResponse[*TimestampResponse]{ResponseMetadata: metadata, Data: &result}, nil
+	}
+
+	// Make the request
+	resp, err := send(ctx, c.httpClient.Request, path, requestBody, headers, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Parse the response
+	var result TimestampResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// Stream converts text to speech and returns a channel of audio chunks with the response metadata
+func (c *RawClient) Stream(ctx context.Context, req StreamRequest, opts ...core.RequestOption) (*core.RawResponse[<-chan []byte], error) {
+	req.VoiceID, req.ModelID = withDefaults(c.httpClient, req.VoiceID, req.ModelID)
+	opts = append([]core.RequestOption{operation("text_to_speech.stream", req.VoiceID, req.ModelID, req.Text), hedging(c.httpClient)}, opts...)
+
+	// Build the request path and query string
+	path, query, err := core.RequestTarget("v1/text-to-speech/{voice_id}/stream", req)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, query)
+
+	// Prepare request body
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "audio/mpeg",
+	}
+
+	// Serve repeated requests from the cache, replaying cached audio as a stream
+	chunkSize := core.NewRequestOptions(opts...).ChunkSizeOr(8192)
+	f := c.cache.lookup(ctx, "stream", cacheKindAudio, req.VoiceID, requestBody, chunkSize, opts, func(ctx context.Context) (*http.Response, error) {
+		return send(ctx, c.httpClient.Stream, path, requestBody, headers, opts)
+	})
+	if f != nil {
+		metadata, err := f.response(ctx)
+		if err != nil {
+			f.release()
+			return nil, err
+		}
+		audio, streamErr := f.stream(ctx)
+		return (&core.RawResponse[<-chan []byte]{ResponseMetadata: metadata, Data: audio}).WithStreamErr(streamErr), nil
+	}
+
+	// Make the streaming request
+	resp, err := send(ctx, c.httpClient.Stream, path, requestBody, headers, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return streaming channel
+	audio, streamErr := core.StreamResponseWithError(resp, chunkSize)
+	return core.NewRawResponse(resp, audio).WithStreamErr(streamErr), nil
+}
+
+// StreamWithTimestamps converts text to speech with timing information in streaming mode and returns the response metadata
+func (c *RawClient) StreamWithTimestamps(ctx context.Context, req StreamRequest, opts ...core.RequestOption) (*core.RawResponse[<-chan TimestampChunk], error) {
+	req.VoiceID, req.ModelID = withDefaults(c.httpClient, req.VoiceID, req.ModelID)
+	opts = append([]core.RequestOption{operation("text_to_speech.stream_with_timestamps", req.VoiceID, req.ModelID, req.Text)}, opts...)
+
+	// Build the request path and query string
+	path, query, err := core.RequestTarget("v1/text-to-speech/{voice_id}/stream/with-timestamps", req)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, query)
+
+	// Prepare request body
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	// Make the streaming request
+	resp, err := c.httpClient.Stream(ctx, "POST", path, bytes.NewReader(requestBody), headers, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// Create channel for timestamp chunks
+	ch := make(chan TimestampChunk)
+	result := &core.StreamResult{}
+
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		// Stream JSON lines
+		lines := core.StreamLines(ctx, resp)
+		for chunk := range lines {
+			if chunk.Err != nil {
+				result.Set(chunk.Err)
+				return
+			}
+
+			// Parse each line as a TimestampChunk
+			var timestampChunk TimestampChunk
+			if err := json.Unmarshal(chunk.Data, &timestampChunk); err == nil {
+				ch <- timestampChunk
+			}
+		}
+	}()
+
+	return core.NewRawResponse(resp, (<-chan TimestampChunk)(ch)).WithStreamErr(result.Err), nil
+}
+
+// withDefaults fills in the client's default voice and model when a request leaves them empty
+func withDefaults(httpClient *core.HTTPClient, voiceID string, modelID *string) (string, *string) {
+	if voiceID == "" {
+		voiceID = httpClient.DefaultVoiceID()
+	}
+	if modelID == nil {
+		if defaultModelID := httpClient.DefaultModelID(); defaultModelID != "" {
+			modelID = &defaultModelID
+		}
+	}
+	return voiceID, modelID
+}
+
+// send makes a request with do and returns the response, or the API error it carries
+func send(ctx context.Context, do func(context.Context, string, string, io.Reader, map[string]string, ...core.RequestOption) (*http.Response, error), path string, body []byte, headers map[string]string, opts []core.RequestOption) (*http.Response, error) {
+	resp, err := do(ctx, "POST", path, bytes.NewReader(body), headers, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, core.ParseAPIError(resp)
+	}
+	return resp, nil
+}
+
+// hedging applies the client's hedging policy, which per-request options can override
+func hedging(httpClient *core.HTTPClient) core.RequestOption {
+	policy := httpClient.TTSHedgePolicy()
+	if policy == nil {
+		return nil
+	}
+	return core.WithHedging(*policy)
+}
@@ -0,0 +1,46 @@
+package text_to_speech
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// newTestClient returns a client with config whose requests are served by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, config core.Config) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	config.APIKey = "test-key"
+	config.Environment = core.Environment{BaseURL: srv.URL}
+	return NewClient(core.NewHTTPClient(config))
+}
+
+func TestStreamWithTimestampsRequestsStreamEndpoint(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text-to-speech/voice-id/stream/with-timestamps" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"audio_base_64":"AAAA","is_final":false}` + "\n" + `{"audio_base_64":"","is_final":true}` + "\n"))
+	}, core.Config{})
+
+	resp, err := client.WithRawResponse.StreamWithTimestamps(context.Background(), StreamRequest{VoiceID: "voice-id", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chunks []TimestampChunk
+	for chunk := range resp.Data {
+		chunks = append(chunks, chunk)
+	}
+	if err := resp.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 2 || chunks[0].AudioBase64 != "AAAA" || !chunks[1].IsFinal {
+		t.Errorf("unexpected chunks %+v", chunks)
+	}
+}
@@ -1,9 +1,16 @@
 package text_to_speech
 
-// ConvertRequest represents a text-to-speech conversion request
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConvertRequest represents a text-to-speech conversion request. VoiceID is sent in the path and the fields
+// tagged url in the query string; the rest form the JSON body.
 type ConvertRequest struct {
 	Text                            string                                   `json:"text"`
-	VoiceID                         string                                   `json:"voice_id"`
+	VoiceID                         string                                   `json:"-" path:"voice_id"`
 	ModelID                         *string                                  `json:"model_id,omitempty"`
 	LanguageCode                    *string                                  `json:"language_code,omitempty"`
 	VoiceSettings                   *VoiceSettings                           `json:"voice_settings,omitempty"`
@@ -16,15 +23,15 @@ type ConvertRequest struct {
 	UsePVCAsIVC                     *bool                                    `json:"use_pvc_as_ivc,omitempty"`
 	ApplyTextNormalization          *TextNormalization                       `json:"apply_text_normalization,omitempty"`
 	ApplyLanguageTextNormalization  *bool                                    `json:"apply_language_text_normalization,omitempty"`
-	EnableLogging                   *bool                                    `json:"enable_logging,omitempty"`
-	OptimizeStreamingLatency        *int                                     `json:"optimize_streaming_latency,omitempty"`
-	OutputFormat                    *OutputFormat                            `json:"output_format,omitempty"`
+	EnableLogging                   *bool                                    `json:"-" url:"enable_logging,omitempty"`
+	OptimizeStreamingLatency        *int                                     `json:"-" url:"optimize_streaming_latency,omitempty"`
+	OutputFormat                    *OutputFormat                            `json:"-" url:"output_format,omitempty"`
 }
 
 // StreamRequest represents a streaming text-to-speech request
 type StreamRequest struct {
 	Text                            string                                   `json:"text"`
-	VoiceID                         string                                   `json:"voice_id"`
+	VoiceID                         string                                   `json:"-" path:"voice_id"`
 	ModelID                         *string                                  `json:"model_id,omitempty"`
 	LanguageCode                    *string                                  `json:"language_code,omitempty"`
 	VoiceSettings                   *VoiceSettings                           `json:"voice_settings,omitempty"`
@@ -37,18 +44,21 @@ type StreamRequest struct {
 	UsePVCAsIVC                     *bool                                    `json:"use_pvc_as_ivc,omitempty"`
 	ApplyTextNormalization          *TextNormalization                       `json:"apply_text_normalization,omitempty"`
 	ApplyLanguageTextNormalization  *bool                                    `json:"apply_language_text_normalization,omitempty"`
-	EnableLogging                   *bool                                    `json:"enable_logging,omitempty"`
-	OptimizeStreamingLatency        *int                                     `json:"optimize_streaming_latency,omitempty"`
-	OutputFormat                    *OutputFormat                            `json:"output_format,omitempty"`
+	EnableLogging                   *bool                                    `json:"-" url:"enable_logging,omitempty"`
+	OptimizeStreamingLatency        *int                                     `json:"-" url:"optimize_streaming_latency,omitempty"`
+	OutputFormat                    *OutputFormat                            `json:"-" url:"output_format,omitempty"`
 }
 
 // RealtimeRequest represents a real-time text-to-speech request
 type RealtimeRequest struct {
-	VoiceID       string         `json:"voice_id"`
-	ModelID       *string        `json:"model_id,omitempty"`
-	OutputFormat  *OutputFormat  `json:"output_format,omitempty"`
+	VoiceID       string         `json:"-" path:"voice_id"`
+	ModelID       *string        `json:"-" url:"model_id,omitempty"`
+	OutputFormat  *OutputFormat  `json:"-" url:"output_format,omitempty"`
 	VoiceSettings *VoiceSettings `json:"voice_settings,omitempty"`
 	TextStream    <-chan string  `json:"-"` // Input text stream
+	// Errors, if set, receives the error that ends the session early, such as an audio frame that cannot be
+	// decoded. It should be buffered; an error is dropped if the channel is full.
+	Errors chan<- error `json:"-"`
 }
 
 // TimestampResponse represents a text-to-speech response with timing information
@@ -103,6 +113,38 @@ const (
 	OutputFormatULAW_8000     OutputFormat = "ulaw_8000"
 )
 
+// AudioDuration estimates the playback duration of size bytes of audio in this format
+func (f OutputFormat) AudioDuration(size int) time.Duration {
+	parts := strings.Split(string(f), "_")
+	if len(parts) < 2 {
+		return 0
+	}
+
+	sampleRate, err := strconv.Atoi(parts[1])
+	if err != nil || sampleRate <= 0 {
+		return 0
+	}
+
+	switch parts[0] {
+	case "pcm":
+		// 16-bit mono samples
+		return time.Duration(float64(size) / float64(sampleRate*2) * float64(time.Second))
+	case "ulaw", "alaw":
+		// 8-bit mono samples
+		return time.Duration(float64(size) / float64(sampleRate) * float64(time.Second))
+	default:
+		// Compressed formats carry their bitrate in kbps as the last component
+		if len(parts) < 3 {
+			return 0
+		}
+		bitrate, err := strconv.Atoi(parts[2])
+		if err != nil || bitrate <= 0 {
+			return 0
+		}
+		return time.Duration(float64(size*8) / float64(bitrate*1000) * float64(time.Second))
+	}
+}
+
 // TextNormalization represents text normalization options
 type TextNormalization string
 
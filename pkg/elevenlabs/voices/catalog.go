@@ -0,0 +1,67 @@
+package voices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// newCatalog creates the catalog of the voices listed by GetAll, refreshed after ttl
+func newCatalog(c *RawClient, ttl time.Duration) *core.Catalog[Voice] {
+	catalog := core.NewCatalog(ttl, c.fetchCatalog,
+		func(voice Voice) string { return voice.VoiceID },
+		func(voice Voice) string { return voice.Name },
+	)
+	catalog.SetClone(cloneVoice)
+	return catalog
+}
+
+// cloneVoice returns a deep copy of a voice, including its labels, settings and samples
+func cloneVoice(voice Voice) Voice {
+	// A voice decoded from JSON encodes and decodes back to itself
+	data, err := json.Marshal(voice)
+	if err != nil {
+		return voice
+	}
+	var clone Voice
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return voice
+	}
+	return clone
+}
+
+// fetchCatalog lists the voices for the catalog, asking the API to skip the list if it still matches etag
+func (c *RawClient) fetchCatalog(ctx context.Context, etag string) ([]Voice, string, error) {
+	reqOpts := []core.RequestOption{operation("voices.get_all", "")}
+	if etag != "" {
+		reqOpts = append(reqOpts, core.WithHeader("If-None-Match", etag))
+	}
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "GET", "v1/voices", nil, nil, reqOpts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, core.ErrNotModified
+	}
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, "", core.ParseAPIError(resp)
+	}
+
+	// Parse the response
+	var result VoicesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Voices, resp.Header.Get("ETag"), nil
+}
@@ -0,0 +1,100 @@
+package voices
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// voicesHandler serves a voice list with an ETag and the deletion of voices
+func voicesHandler(lists *atomic.Int32, notModified *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete:
+			w.Write([]byte(`{}`))
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			lists.Add(1)
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"voices":[{"voice_id":"a","name":"Rachel","labels":{"accent":"american"}},{"voice_id":"b","name":"Adam"}]}`))
+		}
+	}
+}
+
+func TestCatalogServesGetAllAndLookups(t *testing.T) {
+	var lists, notModified atomic.Int32
+	client := newTestClient(t, voicesHandler(&lists, &notModified), core.Config{Catalogs: &core.CatalogConfig{}})
+	ctx := context.Background()
+
+	resp, err := client.GetAll(ctx, GetAllOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Voices) != 2 {
+		t.Fatalf("expected 2 voices, got %+v", resp.Voices)
+	}
+	voice, ok, err := client.Catalog.GetByName(ctx, "rachel")
+	if err != nil || !ok || voice.VoiceID != "a" {
+		t.Fatalf("expected Rachel, got %+v, %v, %v", voice, ok, err)
+	}
+	if got := lists.Load(); got != 1 {
+		t.Errorf("expected one list request, got %d", got)
+	}
+
+	// Request options bypass the catalog
+	if _, err := client.GetAll(ctx, GetAllOptions{}, core.WithHeader("X-Test", "1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := lists.Load(); got != 2 {
+		t.Errorf("expected a call with request options to reach the API, got %d list requests", got)
+	}
+
+	// A refresh asks the API whether the list changed
+	if err := client.Catalog.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := notModified.Load(); got != 1 {
+		t.Errorf("expected a conditional request, got %d", got)
+	}
+}
+
+func TestCatalogReturnsCopies(t *testing.T) {
+	var lists, notModified atomic.Int32
+	client := newTestClient(t, voicesHandler(&lists, &notModified), core.Config{Catalogs: &core.CatalogConfig{}})
+	ctx := context.Background()
+
+	voice, _, _ := client.Catalog.Get(ctx, "a")
+	voice.Labels["accent"] = "changed"
+
+	again, _, _ := client.Catalog.Get(ctx, "a")
+	if again.Labels["accent"] != "american" {
+		t.Errorf("expected the catalog to be unchanged, got %v", again.Labels)
+	}
+}
+
+func TestDeleteRemovesVoiceFromCatalog(t *testing.T) {
+	var lists, notModified atomic.Int32
+	client := newTestClient(t, voicesHandler(&lists, &notModified), core.Config{Catalogs: &core.CatalogConfig{}})
+	ctx := context.Background()
+
+	client.Catalog.All(ctx)
+	if _, err := client.WithRawResponse.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lists.Load(); got != 1 {
+		t.Fatalf("expected one list request before the lookup, got %d", got)
+	}
+
+	// The invalidated catalog fetches the list again, in full
+	if _, ok, _ := client.Catalog.Get(ctx, "b"); !ok {
+		t.Error("expected the other voice to remain")
+	}
+	if got := lists.Load(); got != 2 {
+		t.Errorf("expected the catalog to fetch the full list after the deletion, got %d list requests", got)
+	}
+}
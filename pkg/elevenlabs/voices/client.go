@@ -2,10 +2,6 @@ package voices
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"strings"
 
 	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
 )
@@ -13,164 +9,102 @@ import (
 // Client handles voice operations
 type Client struct {
 	httpClient *core.HTTPClient
+
+	// WithRawResponse performs the same operations and also returns the response metadata
+	WithRawResponse *RawClient
+
+	// Catalog caches the voices listed by GetAll for lookups by ID and name. It is nil unless the client was
+	// configured with catalogs. Deleting a voice or editing its settings through this client invalidates it.
+	Catalog *core.Catalog[Voice]
 }
 
 // NewClient creates a new voices client
 func NewClient(httpClient *core.HTTPClient) *Client {
+	raw := NewRawClient(httpClient)
 	return &Client{
-		httpClient: httpClient,
+		httpClient:      httpClient,
+		WithRawResponse: raw,
+		Catalog:         raw.catalog,
 	}
 }
 
-// GetAll retrieves all available voices
-func (c *Client) GetAll(ctx context.Context, opts GetAllOptions) (*VoicesResponse, error) {
-	path := "v1/voices"
-
-	// Add query parameters
-	params := make([]string, 0)
-	if opts.ShowLegacy != nil {
-		params = append(params, fmt.Sprintf("show_legacy=%t", *opts.ShowLegacy))
-	}
-	if len(params) > 0 {
-		path += "?" + strings.Join(params, "&")
+// GetAll retrieves all available voices. With a catalog, calls without options or request options are served
+// from it; request options such as core.WithAPIKey or core.WithoutCache send the request to the API.
+func (c *Client) GetAll(ctx context.Context, opts GetAllOptions, reqOpts ...core.RequestOption) (*VoicesResponse, error) {
+	if c.Catalog != nil && opts == (GetAllOptions{}) && len(reqOpts) == 0 {
+		voices, err := c.Catalog.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &VoicesResponse{Voices: voices}, nil
 	}
 
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil)
+	resp, err := c.WithRawResponse.GetAll(ctx, opts, reqOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
-	}
-
-	// Parse the response
-	var result VoicesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
+	return resp.Data, nil
+}
 
-	return &result, nil
+// Search returns a pager over the voices matching opts, fetching opts.PageSize voices per request
+func (c *Client) Search(opts SearchOptions, reqOpts ...core.RequestOption) *core.Pager[Voice] {
+	return core.NewPager(func(ctx context.Context, cursor string, pageSize int) (*core.Page[Voice], error) {
+		pageOpts := opts
+		pageOpts.PageSize = pageSize
+
+		resp, err := c.WithRawResponse.Search(ctx, pageOpts, cursor, reqOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &core.Page[Voice]{
+			Items:   resp.Data.Voices,
+			HasMore: resp.Data.HasMore,
+		}
+		if resp.Data.NextPageToken != nil {
+			page.NextCursor = *resp.Data.NextPageToken
+		}
+		return page, nil
+	}, opts.PageSize)
 }
 
 // Get retrieves a specific voice by ID
-func (c *Client) Get(ctx context.Context, voiceID string, opts GetOptions) (*Voice, error) {
-	path := fmt.Sprintf("v1/voices/%s", voiceID)
-
-	// Add query parameters
-	params := make([]string, 0)
-	if opts.WithSettings != nil {
-		params = append(params, fmt.Sprintf("with_settings=%t", *opts.WithSettings))
-	}
-	if len(params) > 0 {
-		path += "?" + strings.Join(params, "&")
-	}
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil)
+func (c *Client) Get(ctx context.Context, voiceID string, opts GetOptions, reqOpts ...core.RequestOption) (*Voice, error) {
+	resp, err := c.WithRawResponse.Get(ctx, voiceID, opts, reqOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
-	}
-
-	// Parse the response
-	var result Voice
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
-
-	return &result, nil
+	return resp.Data, nil
 }
 
 // Delete removes a voice
-func (c *Client) Delete(ctx context.Context, voiceID string) error {
-	path := fmt.Sprintf("v1/voices/%s", voiceID)
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "DELETE", path, nil, nil)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return parseAPIError(resp)
-	}
-
-	return nil
+func (c *Client) Delete(ctx context.Context, voiceID string, reqOpts ...core.RequestOption) error {
+	_, err := c.WithRawResponse.Delete(ctx, voiceID, reqOpts...)
+	return err
 }
 
 // GetSettings retrieves voice settings
-func (c *Client) GetSettings(ctx context.Context, voiceID string) (*VoiceSettings, error) {
-	path := fmt.Sprintf("v1/voices/%s/settings", voiceID)
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil)
+func (c *Client) GetSettings(ctx context.Context, voiceID string, reqOpts ...core.RequestOption) (*VoiceSettings, error) {
+	resp, err := c.WithRawResponse.GetSettings(ctx, voiceID, reqOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
-	}
-
-	// Parse the response
-	var result VoiceSettings
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
-
-	return &result, nil
+	return resp.Data, nil
 }
 
 // EditSettings updates voice settings
-func (c *Client) EditSettings(ctx context.Context, voiceID string, settings VoiceSettings) (*VoiceSettings, error) {
-	path := fmt.Sprintf("v1/voices/%s/settings/edit", voiceID)
-
-	// Prepare request body
-	requestBody, err := json.Marshal(settings)
+func (c *Client) EditSettings(ctx context.Context, voiceID string, settings VoiceSettings, reqOpts ...core.RequestOption) (*VoiceSettings, error) {
+	resp, err := c.WithRawResponse.EditSettings(ctx, voiceID, settings, reqOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
-
-	// Set headers
-	headers := map[string]string{
-		"Content-Type": "application/json",
-	}
-
-	// Make the request
-	resp, err := c.httpClient.Request(ctx, "POST", path, strings.NewReader(string(requestBody)), headers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for errors
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp)
-	}
-
-	// Parse the response
-	var result VoiceSettings
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &result, nil
+	return resp.Data, nil
 }
 
-// parseAPIError parses an HTTP error response
-func parseAPIError(resp *http.Response) error {
-	return fmt.Errorf("API error: %s", resp.Status)
+// operation labels a request with the endpoint and voice
+func operation(endpoint, voiceID string) core.RequestOption {
+	return core.WithOperation(core.Operation{
+		Endpoint: endpoint,
+		VoiceID:  voiceID,
+	})
 }
@@ -0,0 +1,250 @@
+package voices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// RawClient performs voice operations and returns the response metadata alongside each result
+type RawClient struct {
+	httpClient *core.HTTPClient
+	catalog    *core.Catalog[Voice]
+}
+
+// NewRawClient creates a new raw voices client
+func NewRawClient(httpClient *core.HTTPClient) *RawClient {
+	c := &RawClient{
+		httpClient: httpClient,
+	}
+	if config := httpClient.Catalogs(); config != nil {
+		c.catalog = newCatalog(c, config.TTL)
+	}
+	return c
+}
+
+// GetAll retrieves all available voices with the response metadata
+func (c *RawClient) GetAll(ctx context.Context, opts GetAllOptions, reqOpts ...core.RequestOption) (*core.RawResponse[*VoicesResponse], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.get_all", "")}, reqOpts...)
+
+	// Add query parameters
+	query, err := core.EncodeQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+	reqOpts = append(reqOpts, core.WithQuery(query))
+
+	path := "v1/voices"
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// Parse the response
+	var result VoicesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// Search retrieves the page of voices following nextPageToken, or the first page if it is empty,
+// with the response metadata
+func (c *RawClient) Search(ctx context.Context, opts SearchOptions, nextPageToken string, reqOpts ...core.RequestOption) (*core.RawResponse[*SearchResponse], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.search", "")}, reqOpts...)
+
+	// Add query parameters
+	query, err := core.EncodeQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+	if nextPageToken != "" {
+		query.Set("next_page_token", nextPageToken)
+	}
+	reqOpts = append(reqOpts, core.WithQuery(query))
+
+	path := "v2/voices"
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// Parse the response
+	var result SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// Get retrieves a specific voice by ID with the response metadata
+func (c *RawClient) Get(ctx context.Context, voiceID string, opts GetOptions, reqOpts ...core.RequestOption) (*core.RawResponse[*Voice], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.get", voiceID)}, reqOpts...)
+
+	path, err := voicePath("v1/voices/{voice_id}", voiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add query parameters
+	query, err := core.EncodeQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+	reqOpts = append(reqOpts, core.WithQuery(query))
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// Parse the response
+	var result Voice
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// Delete removes a voice and returns the response metadata
+func (c *RawClient) Delete(ctx context.Context, voiceID string, reqOpts ...core.RequestOption) (*core.RawResponse[struct{}], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.delete", voiceID)}, reqOpts...)
+
+	path, err := voicePath("v1/voices/{voice_id}", voiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "DELETE", path, nil, nil, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	if c.catalog != nil {
+		c.catalog.Remove(voiceID)
+	}
+
+	return core.NewRawResponse(resp, struct{}{}), nil
+}
+
+// GetSettings retrieves voice settings with the response metadata
+func (c *RawClient) GetSettings(ctx context.Context, voiceID string, reqOpts ...core.RequestOption) (*core.RawResponse[*VoiceSettings], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.get_settings", voiceID)}, reqOpts...)
+
+	path, err := voicePath("v1/voices/{voice_id}/settings", voiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "GET", path, nil, nil, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// Parse the response
+	var result VoiceSettings
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// EditSettings updates voice settings and returns them with the response metadata
+func (c *RawClient) EditSettings(ctx context.Context, voiceID string, settings VoiceSettings, reqOpts ...core.RequestOption) (*core.RawResponse[*VoiceSettings], error) {
+	reqOpts = append([]core.RequestOption{operation("voices.edit_settings", voiceID)}, reqOpts...)
+
+	path, err := voicePath("v1/voices/{voice_id}/settings/edit", voiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare request body
+	requestBody, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set headers
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	// Make the request
+	resp, err := c.httpClient.Request(ctx, "POST", path, strings.NewReader(string(requestBody)), headers, reqOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for errors
+	if resp.StatusCode >= 400 {
+		return nil, core.ParseAPIError(resp)
+	}
+
+	// The catalog holds the previous settings
+	if c.catalog != nil {
+		c.catalog.Invalidate()
+	}
+
+	// Parse the response
+	var result VoiceSettings
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return core.NewRawResponse(resp, &result), nil
+}
+
+// voiceTarget holds the voice ID filling the path of a request
+type voiceTarget struct {
+	VoiceID string `path:"voice_id"`
+}
+
+// voicePath expands a path template with the escaped voice ID
+func voicePath(template, voiceID string) (string, error) {
+	return core.ExpandPath(template, voiceTarget{VoiceID: voiceID})
+}
@@ -0,0 +1,39 @@
+package voices
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LinkedDestiny/elevenlabs-golang/pkg/elevenlabs/core"
+)
+
+// newTestClient returns a client with config whose requests are served by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, config core.Config) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	config.APIKey = "test-key"
+	config.Environment = core.Environment{BaseURL: srv.URL}
+	return NewClient(core.NewHTTPClient(config))
+}
+
+func TestGetSettingsEscapesVoiceID(t *testing.T) {
+	var gotPath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Write([]byte(`{"stability":0.5}`))
+	}, core.Config{})
+
+	settings, err := client.GetSettings(context.Background(), "a/b?c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/v1/voices/a%2Fb%3Fc/settings" {
+		t.Errorf("expected the voice ID to stay one path segment, got %s", gotPath)
+	}
+	if settings.Stability == nil || *settings.Stability != 0.5 {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+}
@@ -79,12 +79,37 @@ type VoicesResponse struct {
 
 // GetAllOptions represents options for the GetAll method
 type GetAllOptions struct {
-	ShowLegacy *bool `json:"show_legacy,omitempty"`
+	ShowLegacy *bool `json:"show_legacy,omitempty" url:"show_legacy,omitempty"`
+}
+
+// SearchOptions filters and sorts the voices listed by Search
+type SearchOptions struct {
+	// Search matches the name, description, labels and category of a voice
+	Search string `url:"search,omitempty"`
+	// Sort is "created_at_unix" or "name"
+	Sort          string `url:"sort,omitempty"`
+	SortDirection string `url:"sort_direction,omitempty"`
+	// VoiceType is "personal", "community", "default", "workspace", "non-default" or "saved"
+	VoiceType       string   `url:"voice_type,omitempty"`
+	Category        string   `url:"category,omitempty"`
+	FineTuningState string   `url:"fine_tuning_state,omitempty"`
+	CollectionID    string   `url:"collection_id,omitempty"`
+	VoiceIDs        []string `url:"voice_ids,omitempty"`
+	// PageSize bounds the voices fetched per request; zero uses the API default
+	PageSize int `url:"page_size,omitempty"`
+}
+
+// SearchResponse is one page of voices returned by the search endpoint
+type SearchResponse struct {
+	Voices        []Voice `json:"voices"`
+	HasMore       bool    `json:"has_more"`
+	TotalCount    int     `json:"total_count"`
+	NextPageToken *string `json:"next_page_token,omitempty"`
 }
 
 // GetOptions represents options for the Get method
 type GetOptions struct {
-	WithSettings *bool `json:"with_settings,omitempty"`
+	WithSettings *bool `json:"with_settings,omitempty" url:"with_settings,omitempty"`
 }
 
 // VoiceCloneRequest represents a voice cloning request